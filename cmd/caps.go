@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/trynocoding/k8s-toolkit/internal/procinfo"
+)
+
+var capsCmd = &cobra.Command{
+	Use:   "caps",
+	Short: "解码和编码 Linux Capabilities mask",
+	Long: `在没有运行中进程的情况下解码或编码 capability bitmask。
+
+适用于审计日志、docker inspect 输出或问题报告中出现的十六进制 mask。
+
+示例:
+  # 解码 mask（0x 前缀可省略）
+  k8s-toolkit caps decode 0x00000000a80425fb
+
+  # 一次解码多个 mask，以 JSON 输出
+  k8s-toolkit caps decode 00000000a80425fb 000001ffffffffff -o json
+
+  # 将名称列表编码为 mask（CAP_ 前缀可省略）
+  k8s-toolkit caps encode CAP_NET_ADMIN,CAP_SYS_PTRACE
+  k8s-toolkit caps encode net_admin sys_ptrace`,
+}
+
+var capsDecodeCmd = &cobra.Command{
+	Use:   "decode MASK [MASK...]",
+	Short: "将 capability mask 解码为名称列表",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return decodeMasks(args, capsOutput, procinfo.CapabilityMaskDocument)
+	},
+}
+
+var capsEncodeCmd = &cobra.Command{
+	Use:   "encode NAME[,NAME...] [NAME...]",
+	Short: "将 capability 名称列表编码为 mask",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mask, err := procinfo.EncodeCapabilityMask(splitNameArgs(args))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("0x%016x\n", mask)
+		return nil
+	},
+}
+
+var capsOutput string
+
+func init() {
+	rootCmd.AddCommand(capsCmd)
+	capsCmd.AddCommand(capsDecodeCmd)
+	capsCmd.AddCommand(capsEncodeCmd)
+
+	capsDecodeCmd.Flags().StringVarP(&capsOutput, "output", "o", "",
+		"输出格式: json 或 yaml (默认: 可读文本)")
+}
@@ -1,23 +1,35 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/trynocoding/k8s-toolkit/internal/kube"
+	"github.com/trynocoding/k8s-toolkit/internal/multiexec"
+	"github.com/trynocoding/k8s-toolkit/internal/nsenter"
 )
 
 var enterNsCmd = &cobra.Command{
-	Use:   "enter-ns",
-	Short: "进入Pod的网络命名空间",
-	Long: `进入指定Kubernetes Pod的网络命名空间。
+	Use:   "enter-ns (-p POD | -l SELECTOR) [OPTIONS] [-- COMMAND [ARGS...]]",
+	Short: "进入Pod的命名空间",
+	Long: `进入指定Kubernetes Pod的命名空间。
 
-这个命令允许你进入Pod的网络命名空间，可以在其中执行网络调试命令
-（如 ip、tcpdump、netstat 等）。
+默认进入 Pod 的网络和 PID 命名空间，可以在其中执行网络调试命令
+（如 ip、tcpdump、netstat 等）。通过 --ns 可以选择要加入的命名空间：
+  user, cgroup, ipc, uts, net, pid, mnt
+
+在 -- 之后指定命令时，不再启动交互式 shell，而是在目标命名空间中执行该命令，
+透传其标准输入输出并以命令的退出码退出，便于在脚本和 CI 中使用。
+
+使用 --remote 时无需登录到 Pod 所在节点：根据 .spec.nodeName 找到节点地址
+（节点 InternalIP，或 --inventory 中的配置），通过 SSH 打开交互式会话，
+并在节点上执行 k8s-toolkit enter-ns --container-id 进入容器的命名空间。
+
+注意: 加入 mnt 命名空间后看到的是容器的文件系统，shell 也从容器中查找。
+user 命名空间无法由多线程的 Go 进程加入，会被跳过并在输出中说明。
 
 示例:
   # 进入default命名空间中的my-pod
@@ -29,6 +41,38 @@ var enterNsCmd = &cobra.Command{
   # 进入第二个容器的网络命名空间
   k8s-toolkit enter-ns -n default -p my-pod -c 1
 
+  # 按名称选择容器
+  k8s-toolkit enter-ns -p my-pod --container-name sidecar
+
+  # 按标签或工作负载选择 Pod，多个 Pod 运行时提示选择
+  k8s-toolkit enter-ns -l app=nginx
+  k8s-toolkit enter-ns -p deploy/nginx
+  k8s-toolkit enter-ns -p sts/redis
+
+  # 通过 CRI 接口获取 PID (CRI-O 或使用非默认 socket 的 containerd)
+  k8s-toolkit enter-ns -p my-pod -r cri --cri-socket /run/k3s/containerd/containerd.sock
+
+  # 进入正在运行的 init 容器或 kubectl debug 创建的 ephemeral 容器
+  k8s-toolkit enter-ns -p my-pod --container-name debugger-abcde
+
+  # 进入 mnt 和 uts 命名空间，查看容器的文件系统
+  k8s-toolkit enter-ns -p my-pod --ns mnt,uts
+
+  # 进入所有命名空间
+  k8s-toolkit enter-ns -p my-pod --all
+
+  # 在 Pod 网络命名空间中执行一次性命令
+  k8s-toolkit enter-ns -p my-pod -- ss -tnlp
+
+  # 抓包
+  k8s-toolkit enter-ns -p my-pod -- tcpdump -i eth0 -c 100 port 80
+
+  # 自动 SSH 到 Pod 所在节点并进入命名空间
+  k8s-toolkit enter-ns -p my-pod --remote --ssh-user ops --sudo
+
+  # 使用节点清单覆盖节点地址
+  k8s-toolkit enter-ns -p my-pod --remote --inventory ./nodes.txt
+
   # 详细模式
   k8s-toolkit enter-ns -p my-pod -v`,
 	RunE: runEnterNs,
@@ -36,26 +80,74 @@ var enterNsCmd = &cobra.Command{
 
 var (
 	podName        string
+	podSelector    string
 	namespace      string
 	containerIndex int
+	containerName  string
 	runtime        string
+	criSocket      string
+	nsList         []string
+	nsAll          bool
+	containerID    string
+
+	// 远程进入相关参数
+	remote          bool
+	remoteInventory string
+	remoteUser      string
+	remotePassword  string
+	remoteIdentity  string
+	remotePort      string
+	remoteSudo      bool
+	remoteBin       string
 )
 
 func init() {
 	rootCmd.AddCommand(enterNsCmd)
 
-	// 必需参数
+	// Pod 选择参数（-p 与 -l 二选一，指定 --container-id 时可省略）
 	enterNsCmd.Flags().StringVarP(&podName, "pod", "p", "",
-		"Pod 名称 (必需)")
-	enterNsCmd.MarkFlagRequired("pod")
+		"Pod 名称，或 deploy/NAME、sts/NAME 等工作负载引用")
+	enterNsCmd.Flags().StringVarP(&podSelector, "selector", "l", "",
+		"Pod 标签选择器，如 app=foo (与 --pod 互斥)")
+	enterNsCmd.MarkFlagsMutuallyExclusive("pod", "selector")
 
 	// 可选参数
 	enterNsCmd.Flags().StringVarP(&namespace, "namespace", "n", "default",
 		"Kubernetes 命名空间")
 	enterNsCmd.Flags().IntVarP(&containerIndex, "container", "c", 0,
-		"容器索引 (默认: 0，即第一个容器)")
+		"容器索引，按 spec.containers 声明顺序 (默认: 0，即第一个容器)")
+	enterNsCmd.Flags().StringVar(&containerName, "container-name", "",
+		"容器名称，支持 init 容器和 ephemeral 调试容器 (与 --container 互斥)")
+	enterNsCmd.MarkFlagsMutuallyExclusive("container", "container-name")
 	enterNsCmd.Flags().StringVarP(&runtime, "runtime", "r", "auto",
-		"容器运行时 (containerd|docker|auto)")
+		"容器运行时 (containerd|docker|cri|auto)")
+	enterNsCmd.Flags().StringVar(&criSocket, "cri-socket", "",
+		"CRI 运行时 socket 路径 (默认依次探测 containerd、CRI-O、cri-dockerd 的默认 socket)")
+	enterNsCmd.Flags().StringSliceVar(&nsList, "ns", nsenter.DefaultNamespaces,
+		"要加入的命名空间，逗号分隔 (user,cgroup,ipc,uts,net,pid,mnt)")
+	enterNsCmd.Flags().BoolVar(&nsAll, "all", false,
+		"加入所有命名空间")
+	enterNsCmd.MarkFlagsMutuallyExclusive("ns", "all")
+	enterNsCmd.Flags().StringVar(&containerID, "container-id", "",
+		"直接指定容器 ID，可带运行时前缀如 containerd://ID (跳过 Pod 查询，--remote 在节点上使用)")
+
+	// 远程进入参数
+	enterNsCmd.Flags().BoolVar(&remote, "remote", false,
+		"通过 SSH 登录 Pod 所在节点后进入命名空间 (节点上需安装 k8s-toolkit)")
+	enterNsCmd.Flags().StringVar(&remoteInventory, "inventory", "",
+		"节点清单文件，每行 \"<节点名> <地址[:端口]>\"，覆盖节点的 InternalIP")
+	enterNsCmd.Flags().StringVar(&remoteUser, "ssh-user", "",
+		"SSH 用户名 (默认: 当前用户)")
+	enterNsCmd.Flags().StringVar(&remotePassword, "ssh-password", "",
+		"SSH 密码 (可选)")
+	enterNsCmd.Flags().StringVar(&remoteIdentity, "ssh-identity", "",
+		"SSH 私钥路径 (可选，默认使用 ~/.ssh/id_rsa 等)")
+	enterNsCmd.Flags().StringVar(&remotePort, "ssh-port", "22",
+		"SSH 端口 (默认: 22)")
+	enterNsCmd.Flags().BoolVar(&remoteSudo, "sudo", false,
+		"在节点上使用 sudo 执行")
+	enterNsCmd.Flags().StringVar(&remoteBin, "remote-bin", "k8s-toolkit",
+		"节点上 k8s-toolkit 的路径")
 
 	// 注册补全函数
 	registerCompletionFuncs()
@@ -89,86 +181,326 @@ func registerCompletionFuncs() {
 			return pods, cobra.ShellCompDirectiveNoFileComp
 		})
 
+	// container-name 补全（根据当前 pod 的 spec）
+	enterNsCmd.RegisterFlagCompletionFunc("container-name", completeContainerNames)
+
 	// runtime 静态补全
 	enterNsCmd.RegisterFlagCompletionFunc("runtime",
 		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			return []string{"auto", "containerd", "docker"}, cobra.ShellCompDirectiveNoFileComp
+			return []string{"auto", "containerd", "docker", "cri"}, cobra.ShellCompDirectiveNoFileComp
+		})
+
+	// ns 静态补全
+	enterNsCmd.RegisterFlagCompletionFunc("ns",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return nsenter.AllNamespaces, cobra.ShellCompDirectiveNoFileComp
 		})
 }
 
 // getNamespaces 获取所有 Kubernetes namespace
 func getNamespaces() ([]string, error) {
-	cmd := exec.Command("kubectl", "get", "namespaces",
-		"-o", "jsonpath={.items[*].metadata.name}")
-	output, err := cmd.Output()
+	client, err := newKubeClient()
 	if err != nil {
 		return nil, err
 	}
-	return strings.Fields(string(output)), nil
+	return client.ListNamespaces(context.Background())
 }
 
 // getPodNames 获取指定 namespace 下的所有 pod
 func getPodNames(namespace string) ([]string, error) {
-	cmd := exec.Command("kubectl", "get", "pods", "-n", namespace,
-		"-o", "jsonpath={.items[*].metadata.name}")
-	output, err := cmd.Output()
+	client, err := newKubeClient()
 	if err != nil {
 		return nil, err
 	}
-	return strings.Fields(string(output)), nil
+	return client.ListPodNames(context.Background(), namespace)
+}
+
+// completeContainerNames 根据 --pod/--selector 和 --namespace 补全容器名称
+// Pod 的解析方式与命令本身一致；匹配多个 Pod 时不提示选择，使用第一个 (同一工作负载的容器相同)
+func completeContainerNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ref, _ := cmd.Flags().GetString("pod")
+	selector, _ := cmd.Flags().GetString("selector")
+	if ref == "" && selector == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ns, _ := cmd.Flags().GetString("namespace")
+	if ns == "" {
+		ns = "default"
+	}
+
+	client, err := newKubeClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	pods, _, err := candidatePods(context.Background(), client, ns, ref, selector)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	p := &pods[0]
+
+	// 补全项附带容器类型说明 (container/init/ephemeral)
+	var completions []string
+	for _, c := range kube.ListContainers(p) {
+		completions = append(completions, c.Name+"\t"+string(c.Kind))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
 }
 
 func runEnterNs(cmd *cobra.Command, args []string) error {
 	verbose, _ := cmd.Flags().GetBool("verbose")
+	ctx := context.Background()
+
+	// -- 之后的参数为一次性执行的命令
+	if len(args) > 0 && cmd.ArgsLenAtDash() != 0 {
+		return fmt.Errorf("未知参数: %v (要执行的命令需放在 -- 之后)", args)
+	}
+	oneShot := len(args) > 0
+
+	// 一次性命令模式下，提示信息输出到 stderr，保持 stdout 只包含命令输出
+	logOut := os.Stdout
+	if oneShot {
+		logOut = os.Stderr
+	}
+
+	if podName == "" && podSelector == "" && containerID == "" {
+		return fmt.Errorf("必须指定 Pod (使用 -p 指定名称，或 -l 指定标签选择器)")
+	}
+	if remote && containerID != "" {
+		return fmt.Errorf("--remote 与 --container-id 不能同时使用")
+	}
+
+	// 解析要加入的命名空间
+	requested := nsList
+	if nsAll {
+		requested = nsenter.AllNamespaces
+	}
+	namespaces, err := nsenter.ParseNamespaces(requested)
+	if err != nil {
+		return err
+	}
+
+	// 1. 确定目标容器：直接指定容器 ID，或获取 Pod 并解析容器
+	var target *nsenter.ContainerTarget
+	var nodeName string
+	if containerID != "" {
+		hint, id := kube.ParseContainerID(containerID)
+		target = &nsenter.ContainerTarget{
+			PodName:     podName,
+			Namespace:   namespace,
+			Name:        containerName,
+			ID:          id,
+			RuntimeHint: hint,
+		}
+		if target.Name == "" {
+			target.Name = shortID(id)
+		}
+	} else {
+		if verbose {
+			fmt.Fprintf(logOut, "[DEBUG] 获取 Pod '%s' 在命名空间 '%s' 中的容器信息...\n", podDisplayRef(podName, podSelector), namespace)
+		}
+		client, err := newKubeClient()
+		if err != nil {
+			return err
+		}
+		pod, err := resolvePod(ctx, client, namespace, podName, podSelector)
+		if err != nil {
+			return err
+		}
+
+		container, err := kube.ResolveContainer(pod, kube.ContainerSelector{Name: containerName, Index: containerIndex})
+		if err != nil {
+			return err
+		}
+
+		target, err = nsenter.NewContainerTarget(container)
+		if err != nil {
+			return err
+		}
+		nodeName = pod.Spec.NodeName
+	}
+	if verbose {
+		fmt.Fprintf(logOut, "[DEBUG] 目标容器: %s\n", target.Name)
+		fmt.Fprintf(logOut, "[DEBUG] 容器 ID: %s\n", target.ID)
+	}
+	if !oneShot || verbose {
+		fmt.Fprintf(logOut, "[INFO] Pod: %s, 命名空间: %s, 容器: %s\n", target.PodName, target.Namespace, target.Name)
+	}
+
+	var exitCode int
+	if remote {
+		exitCode, err = runEnterNsRemote(ctx, target, nodeName, namespaces, args, verbose, logOut)
+	} else {
+		exitCode, err = runEnterNsLocal(ctx, target, namespaces, args, verbose, logOut)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 保留 shell 或命令的退出码
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+
+	return nil
+}
+
+// runEnterNsLocal 在本机获取容器 PID，加入命名空间后启动 shell 或执行命令
+func runEnterNsLocal(ctx context.Context, target *nsenter.ContainerTarget, namespaces, command []string, verbose bool, logOut *os.File) (int, error) {
+	oneShot := len(command) > 0
 
 	// 检查是否有root权限
 	if os.Geteuid() != 0 {
-		return fmt.Errorf("此命令需要root权限运行，请使用: sudo %s", os.Args[0])
+		return 0, fmt.Errorf("此命令需要root权限运行，请使用: sudo %s", os.Args[0])
 	}
 
-	// 创建临时脚本文件
-	tmpDir, err := ioutil.TempDir("", "k8s-toolkit-*")
+	// 2. 通过容器运行时获取 PID
+	resolver, err := nsenter.NewPIDResolver(ctx, nsenter.ResolverOptions{
+		Runtime:   runtime,
+		Hint:      target.RuntimeHint,
+		CRISocket: criSocket,
+	})
 	if err != nil {
-		return fmt.Errorf("创建临时目录失败: %w", err)
+		return 0, err
+	}
+	pid, err := resolver.ContainerPID(ctx, target.ID)
+	resolver.Close()
+	if err != nil {
+		return 0, fmt.Errorf("获取容器 PID 失败: %w", err)
+	}
+	if verbose {
+		fmt.Fprintf(logOut, "[DEBUG] 容器运行时: %s, 容器 PID: %d\n", resolver.Name(), pid)
 	}
-	defer os.RemoveAll(tmpDir)
 
-	scriptPath := filepath.Join(tmpDir, "enter_pod_ns.sh")
-	if err := ioutil.WriteFile(scriptPath, []byte(enterPodNsScript), 0755); err != nil {
-		return fmt.Errorf("写入脚本文件失败: %w", err)
+	// 3. 加入命名空间并启动 shell
+	results, err := nsenter.JoinNamespaces(pid, namespaces)
+	if err != nil {
+		return 0, err
 	}
 
-	// 构建bash命令参数
-	bashArgs := []string{scriptPath, podName, namespace}
+	joined := nsenter.JoinedNames(results)
+	for _, r := range results {
+		if !r.Joined {
+			fmt.Fprintf(os.Stderr, "[WARN] 未加入 %s 命名空间: %s\n", r.Name, r.Reason)
+		}
+	}
 
-	// 添加选项
-	if containerIndex != 0 {
-		bashArgs = append(bashArgs, "-c", fmt.Sprintf("%d", containerIndex))
+	if oneShot {
+		if verbose {
+			fmt.Fprintf(logOut, "[INFO] 已加入命名空间: %s\n", formatNsNames(joined))
+		}
+		return nsenter.RunCommand(nsenter.ExecOptions{
+			Command: command,
+			Verbose: verbose,
+		})
 	}
-	if runtime != "auto" {
-		bashArgs = append(bashArgs, "-r", runtime)
+
+	fmt.Printf("[SUCCESS] 进入容器命名空间 (PID: %d)\n", pid)
+	fmt.Printf("[INFO] 已加入命名空间: %s\n", formatNsNames(joined))
+	fmt.Printf("[INFO] 您现在处于 Pod '%s' 容器 '%s' 的命名空间中\n", target.PodName, target.Name)
+	fmt.Println("[INFO] 使用 'exit' 退出命名空间")
+
+	return nsenter.RunShell(nsenter.ExecOptions{
+		Prompt:  fmt.Sprintf("[%s:%s/%s] \\u@\\h:\\w\\$ ", formatNsNames(joined), target.PodName, target.Name),
+		Verbose: verbose,
+	})
+}
+
+// runEnterNsRemote 通过 SSH 登录 Pod 所在节点，在节点上以 --container-id 方式执行 enter-ns
+func runEnterNsRemote(ctx context.Context, target *nsenter.ContainerTarget, nodeName string, namespaces, command []string, verbose bool, logOut *os.File) (int, error) {
+	if nodeName == "" {
+		return 0, fmt.Errorf("Pod %s/%s 尚未调度到节点", target.Namespace, target.PodName)
+	}
+
+	// 节点地址: 优先使用节点清单中的配置，否则使用节点的 InternalIP
+	address := ""
+	if remoteInventory != "" {
+		inventory, err := multiexec.LoadInventory(remoteInventory)
+		if err != nil {
+			return 0, err
+		}
+		address = inventory[nodeName]
+	}
+	if address == "" {
+		client, err := newKubeClient()
+		if err != nil {
+			return 0, err
+		}
+		ip, err := client.GetNodeInternalIP(ctx, nodeName)
+		if err != nil {
+			return 0, fmt.Errorf("获取节点 %s 的地址失败: %w", nodeName, err)
+		}
+		address = ip
+	}
+
+	// 构造节点上执行的 enter-ns 命令，容器 ID 带上运行时前缀以便远端选择运行时
+	remoteArgs := []string{remoteBin, "enter-ns",
+		"--container-id", target.RuntimeHint + "://" + target.ID,
+		"-p", target.PodName,
+		"-n", target.Namespace,
+		"--container-name", target.Name,
+		"--runtime", runtime,
+		"--ns", strings.Join(namespaces, ","),
+	}
+	if target.RuntimeHint == "" {
+		remoteArgs[3] = target.ID
+	}
+	if criSocket != "" {
+		remoteArgs = append(remoteArgs, "--cri-socket", criSocket)
 	}
 	if verbose {
-		bashArgs = append(bashArgs, "-v")
+		remoteArgs = append(remoteArgs, "-v")
+	}
+	if len(command) > 0 {
+		remoteArgs = append(remoteArgs, "--")
+		remoteArgs = append(remoteArgs, command...)
 	}
 
-	// 执行bash脚本
-	bashCmd := exec.Command("bash", bashArgs...)
-	bashCmd.Stdin = os.Stdin
-	bashCmd.Stdout = os.Stdout
-	bashCmd.Stderr = os.Stderr
+	opts := multiexec.ExecOptions{
+		Command:  shellJoin(remoteArgs),
+		User:     remoteUser,
+		Password: remotePassword,
+		Identity: remoteIdentity,
+		Port:     remotePort,
+		Sudo:     remoteSudo,
+		Verbose:  verbose,
+	}
 
+	sshConfig, err := multiexec.BuildSSHClientConfig(opts)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(command) == 0 || verbose {
+		fmt.Fprintf(logOut, "[INFO] Pod 运行在节点 %s (%s)，通过 SSH 进入\n", nodeName, address)
+	}
 	if verbose {
-		fmt.Printf("[DEBUG] 执行命令: bash %v\n", bashArgs)
+		fmt.Fprintf(logOut, "[DEBUG] 远程命令: %s\n", opts.Command)
 	}
 
-	if err := bashCmd.Run(); err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			// 保留bash脚本的退出码
-			os.Exit(exitErr.ExitCode())
-		}
-		return fmt.Errorf("执行脚本失败: %w", err)
+	return multiexec.RunInteractive(address, opts, sshConfig)
+}
+
+// formatNsNames 格式化命名空间列表
+func formatNsNames(names []string) string {
+	if len(names) == 0 {
+		return "<none>"
 	}
+	return strings.Join(names, ",")
+}
 
-	return nil
+// shortID 返回容器 ID 的前 12 位
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// shellJoin 将参数用单引号转义后拼接为 shell 命令
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
 }
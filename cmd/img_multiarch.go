@@ -37,6 +37,9 @@ var multiarchCmd = &cobra.Command{
   # 完成后清理临时标签和 tar 文件
   k8s-toolkit img-multiarch -i redis:7 -a amd64,arm64 -c
 
+  # 私有仓库（默认读取 ~/.docker/config.json 中的凭据）
+  echo "$PASSWORD" | k8s-toolkit img-multiarch -i harbor.example.com/app/api:v1 -a amd64,arm64 --registry-user robot --registry-password-stdin
+
   # 详细模式查看每个步骤
   k8s-toolkit img-multiarch -i alpine:3.18 -a amd64,arm64 -v`,
 	RunE: runMultiarch,
@@ -47,6 +50,10 @@ var (
 	architectures    string
 	multiarchOutput  string
 	multiarchCleanup bool
+
+	multiarchRegistry              string
+	multiarchRegistryUser          string
+	multiarchRegistryPasswordStdin bool
 )
 
 func init() {
@@ -64,6 +71,12 @@ func init() {
 		"输出目录")
 	multiarchCmd.Flags().BoolVarP(&multiarchCleanup, "cleanup", "c", false,
 		"完成后清理临时镜像标签和 tar 文件")
+	multiarchCmd.Flags().StringVar(&multiarchRegistry, "registry", "",
+		"--registry-user 凭据适用的镜像仓库 (默认为镜像所在的仓库，其他仓库仍使用 ~/.docker/config.json)")
+	multiarchCmd.Flags().StringVar(&multiarchRegistryUser, "registry-user", "",
+		"镜像仓库用户名 (默认使用 ~/.docker/config.json 中的凭据)")
+	multiarchCmd.Flags().BoolVar(&multiarchRegistryPasswordStdin, "registry-password-stdin", false,
+		"从标准输入读取镜像仓库密码")
 
 	// 注册补全函数
 	registerMultiarchCompletions()
@@ -107,6 +120,11 @@ func runMultiarch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("架构列表不能为空")
 	}
 
+	creds, err := loadRegistryCredentials(multiarchRegistry, multiarchRegistryUser, multiarchRegistryPasswordStdin, multiarchImage)
+	if err != nil {
+		return err
+	}
+
 	// 打印任务信息
 	fmt.Printf("========== 多架构镜像拉取 ==========\n")
 	fmt.Printf("镜像: %s\n", multiarchImage)
@@ -121,6 +139,7 @@ func runMultiarch(cmd *cobra.Command, args []string) error {
 		OutputDir:     multiarchOutput,
 		Cleanup:       multiarchCleanup,
 		Verbose:       verbose,
+		Credentials:   creds,
 		ProgressCb: func(stage string, progress float64, message string) {
 			if verbose {
 				fmt.Printf("[%s] %s\n", stage, message)
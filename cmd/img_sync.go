@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -11,14 +12,31 @@ import (
 )
 
 var imgSyncCmd = &cobra.Command{
-	Use:   "img-sync -i IMAGE [OPTIONS]",
+	Use:   "img-sync {-i IMAGE | -f FILE} [OPTIONS]",
 	Short: "Docker镜像同步和分发工具",
 	Long: `拉取Docker镜像，流式导入到containerd，并可选地分发到远程节点。
 
 这个工具自动化了镜像迁移流程（使用 Go 原生 SDK，无临时文件）:
 1. 使用 Docker SDK 拉取镜像
 2. 流式传输到 Containerd（无中间文件）
-3. (可选) 通过 SSH 流式分发到远程节点
+3. (可选) 通过 SSH 流式分发到远程节点，只传输节点 containerd 中缺少的 layer
+
+使用 -f 批量同步时，镜像以 -j 为上限并发拉取，每个节点只建立一个 SSH 连接并逐个导入，
+最后输出镜像 × 节点的结果矩阵。YAML 清单格式:
+
+  nodeGroups:
+    gpu: [gpu-1, gpu-2]
+  images:
+    - image: nginx:1.27
+      platforms: [linux/amd64, linux/arm64]   # 默认本机平台
+      nodes: [gpu, node3]                     # 节点组或节点，默认 -n 指定的节点
+    - image: redis:7
+
+重复执行时会先比较镜像 digest: 本地 containerd 已有相同镜像时跳过导入，远程节点通过
+ctr images ls 查询，已有相同 digest 的节点跳过传输并报告为 "已是最新"。
+
+使用 --source registry 时不需要 Docker: 通过 containerd 直接从镜像仓库拉取本机平台的镜像并解包，
+再从 containerd 导出分发到远程节点。镜像仓库的 mirror 和证书配置读取 /etc/containerd/certs.d。
 
 示例:
   # 拉取并同步nginx镜像
@@ -27,25 +45,57 @@ var imgSyncCmd = &cobra.Command{
   # 同步并分发到远程节点
   k8s-toolkit img-sync -i redis:alpine -n node1,node2,node3
 
+  # 没有 Docker 的节点：直接从镜像仓库拉取到 containerd
+  k8s-toolkit img-sync -i nginx:latest --source registry -n node1,node2
+
+  # 私有仓库：默认读取 ~/.docker/config.json（auths、credsStore、credHelpers），也可以显式指定
+  echo "$PASSWORD" | k8s-toolkit img-sync -i harbor.example.com/app/api:v1 --registry-user robot --registry-password-stdin
+
+  # 批量同步：每行一个镜像，或使用 YAML 清单为每个镜像指定平台和节点组
+  k8s-toolkit img-sync -f images.txt -n node1,node2 -j 4
+  k8s-toolkit img-sync -f release.yaml --source registry
+  echo "$PASSWORD" | k8s-toolkit img-sync -f images.txt --registry harbor.example.com --registry-user robot --registry-password-stdin
+
   # 详细模式查看执行过程
   k8s-toolkit img-sync -i nginx:latest -v`,
 	RunE: runImgSync,
 }
 
 var (
-	imageName string
-	nodes     string
-	outputDir string
-	cleanup   bool
+	imageName   string
+	imageFile   string
+	imageSource string
+	nodes       string
+	outputDir   string
+	cleanup     bool
+
+	syncParallel int
+
+	syncRegistry              string
+	syncRegistryUser          string
+	syncRegistryPasswordStdin bool
 )
 
 func init() {
 	rootCmd.AddCommand(imgSyncCmd)
 
 	imgSyncCmd.Flags().StringVarP(&imageName, "image", "i", "",
-		"要处理的镜像名称 (必需)")
-	imgSyncCmd.MarkFlagRequired("image")
-
+		"要处理的镜像名称 (与 -f 二选一)")
+	imgSyncCmd.Flags().StringVarP(&imageFile, "file", "f", "",
+		"镜像清单文件: 每行一个镜像，或 .yaml/.yml 格式的清单 (与 -i 二选一)")
+	imgSyncCmd.Flags().IntVarP(&syncParallel, "parallel", "j", 3,
+		"批量同步时同时拉取的镜像数")
+	imgSyncCmd.MarkFlagsOneRequired("image", "file")
+	imgSyncCmd.MarkFlagsMutuallyExclusive("image", "file")
+
+	imgSyncCmd.Flags().StringVar(&imageSource, "source", imgsync.SourceDocker,
+		"镜像来源: docker (通过 Docker daemon 拉取) 或 registry (通过 containerd 直接从镜像仓库拉取)")
+	imgSyncCmd.Flags().StringVar(&syncRegistry, "registry", "",
+		"--registry-user 凭据适用的镜像仓库 (默认为镜像所在的仓库，其他仓库仍使用 ~/.docker/config.json)")
+	imgSyncCmd.Flags().StringVar(&syncRegistryUser, "registry-user", "",
+		"镜像仓库用户名 (默认使用 ~/.docker/config.json 中的凭据)")
+	imgSyncCmd.Flags().BoolVar(&syncRegistryPasswordStdin, "registry-password-stdin", false,
+		"从标准输入读取镜像仓库密码")
 	imgSyncCmd.Flags().StringVarP(&nodes, "nodes", "n", "",
 		"远程节点列表，逗号分隔 (例如: node1,node2)")
 	imgSyncCmd.Flags().StringVarP(&outputDir, "output-dir", "d", "./images",
@@ -65,6 +115,14 @@ func registerImgSyncCompletions() {
 			// TODO: 可以从配置文件或 SSH 配置中读取节点列表
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		})
+
+	imgSyncCmd.MarkFlagFilename("file", "txt", "yaml", "yml")
+
+	// 镜像来源补全
+	imgSyncCmd.RegisterFlagCompletionFunc("source",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{imgsync.SourceDocker, imgsync.SourceRegistry}, cobra.ShellCompDirectiveNoFileComp
+		})
 }
 
 func runImgSync(cmd *cobra.Command, args []string) error {
@@ -72,8 +130,8 @@ func runImgSync(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
 	// 验证镜像名称
-	if imageName == "" {
-		return fmt.Errorf("必须指定镜像名称 (使用 -i 或 --image)")
+	if imageName == "" && imageFile == "" {
+		return fmt.Errorf("必须指定镜像名称 (使用 -i 或 --image) 或镜像清单 (使用 -f 或 --file)")
 	}
 
 	// 解析节点列表
@@ -85,23 +143,24 @@ func runImgSync(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	creds, err := loadRegistryCredentials(syncRegistry, syncRegistryUser, syncRegistryPasswordStdin, imageName)
+	if err != nil {
+		return err
+	}
+
+	if imageFile != "" {
+		return runImgSyncBatch(ctx, nodeList, creds, verbose)
+	}
+
 	// 创建同步选项
 	opts := imgsync.SyncOptions{
-		OutputDir: outputDir,
-		Nodes:     nodeList,
-		Cleanup:   cleanup,
-		Verbose:   verbose,
-		ProgressCb: func(stage string, progress float64, message string) {
-			if verbose {
-				fmt.Printf("[%s] %s\n", stage, message)
-			} else {
-				// 简洁模式：只显示关键阶段
-				switch stage {
-				case "拉取", "同步", "分发", "完成":
-					fmt.Printf("[%s] %s\n", stage, message)
-				}
-			}
-		},
+		Source:      imageSource,
+		Credentials: creds,
+		OutputDir:   outputDir,
+		Nodes:       nodeList,
+		Cleanup:     cleanup,
+		Verbose:     verbose,
+		ProgressCb:  syncProgress(verbose),
 	}
 
 	// 执行同步
@@ -113,26 +172,58 @@ func runImgSync(cmd *cobra.Command, args []string) error {
 	// 输出结果
 	fmt.Println("\n========== 同步结果 ==========")
 	fmt.Printf("镜像: %s\n", result.ImageName)
-	fmt.Printf("本地导入: %v\n", result.LocalImported)
+	if result.Digest != "" {
+		fmt.Printf("Digest: %s\n", result.Digest)
+	}
+	if result.LocalUpToDate {
+		fmt.Println("本地导入: 已是最新 (跳过导入)")
+	} else {
+		fmt.Printf("本地导入: %v\n", result.LocalImported)
+	}
 	fmt.Printf("耗时: %v\n", result.Duration)
 
+	failed := false
 	if len(result.RemoteNodes) > 0 {
 		fmt.Println("\n远程节点状态:")
-		for node, nodeErr := range result.RemoteNodes {
-			if nodeErr != nil {
-				fmt.Printf("  ❌ %s: %v\n", node, nodeErr)
-			} else {
-				fmt.Printf("  ✅ %s: 成功\n", node)
+		names := make([]string, 0, len(result.RemoteNodes))
+		for node := range result.RemoteNodes {
+			names = append(names, node)
+		}
+		sort.Strings(names)
+		for _, node := range names {
+			r := result.RemoteNodes[node]
+			switch {
+			case r.Err != nil:
+				failed = true
+				fmt.Printf("  ❌ %s: %v\n", node, r.Err)
+			case r.UpToDate:
+				fmt.Printf("  ⏭️  %s: 已是最新 (up to date)\n", node)
+			case r.Reused > 0:
+				fmt.Printf("  ✅ %s: 同步成功 (传输 %s，复用节点已有 layer %s)\n", node, formatBytes(r.Transferred), formatBytes(r.Reused))
+			default:
+				fmt.Printf("  ✅ %s: 同步成功 (传输 %s)\n", node, formatBytes(r.Transferred))
 			}
 		}
 	}
 
-	// 检查是否有失败的节点
-	for _, nodeErr := range result.RemoteNodes {
-		if nodeErr != nil {
-			os.Exit(1)
-		}
+	// 有失败的节点时以非零退出码结束
+	if failed {
+		os.Exit(1)
 	}
 
 	return nil
 }
+
+// syncProgress 返回输出同步进度的回调，简洁模式只显示关键阶段
+func syncProgress(verbose bool) func(stage string, progress float64, message string) {
+	return func(stage string, progress float64, message string) {
+		if verbose {
+			fmt.Printf("[%s] %s\n", stage, message)
+			return
+		}
+		switch stage {
+		case "拉取", "同步", "分发", "完成":
+			fmt.Printf("[%s] %s\n", stage, message)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/trynocoding/k8s-toolkit/internal/imgsync"
+)
+
+// runImgSyncBatch 按镜像清单批量同步，nodeList 是没有指定节点的镜像的目标节点
+func runImgSyncBatch(ctx context.Context, nodeList []string, creds *imgsync.Credentials, verbose bool) error {
+	if syncParallel < 1 {
+		return fmt.Errorf("--parallel 必须大于 0")
+	}
+
+	manifest, err := imgsync.LoadBatchFile(imageFile)
+	if err != nil {
+		return err
+	}
+	images, err := manifest.Resolve(nodeList)
+	if err != nil {
+		return err
+	}
+
+	result, err := imgsync.SyncImages(ctx, images, imgsync.BatchOptions{
+		Source:      imageSource,
+		Credentials: creds,
+		Parallel:    syncParallel,
+		Verbose:     verbose,
+		ProgressCb:  syncProgress(verbose),
+	})
+	if err != nil {
+		return fmt.Errorf("同步失败: %w", err)
+	}
+
+	fmt.Println("\n========== 同步结果 ==========")
+	printBatchMatrix(result)
+	fmt.Printf("\n耗时: %v\n", result.Duration)
+
+	if failures := batchFailures(result); len(failures) > 0 {
+		fmt.Println("\n失败详情:")
+		for _, f := range failures {
+			fmt.Printf("  ❌ %s\n", f)
+		}
+		os.Exit(1)
+	}
+	return nil
+}
+
+// printBatchMatrix 输出镜像 × 节点的结果矩阵
+// 单元格: synced (同步成功)、up-to-date (已是最新)、FAILED (失败)、- (不是该镜像的目标节点或拉取失败)
+func printBatchMatrix(result *imgsync.BatchResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := append([]string{"IMAGE", "LOCAL"}, result.Nodes...)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for _, img := range result.Images {
+		row := []string{img.ImageName}
+		switch {
+		case img.Err != nil:
+			row = append(row, "FAILED")
+		case img.LocalUpToDate:
+			row = append(row, "up-to-date")
+		default:
+			row = append(row, "imported")
+		}
+
+		for _, node := range result.Nodes {
+			r, ok := img.Nodes[node]
+			switch {
+			case !ok:
+				row = append(row, "-")
+			case r.Err != nil:
+				row = append(row, "FAILED")
+			case r.UpToDate:
+				row = append(row, "up-to-date")
+			default:
+				row = append(row, "synced")
+			}
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
+
+// batchFailures 按镜像和节点的顺序列出失败的原因
+func batchFailures(result *imgsync.BatchResult) []string {
+	var failures []string
+	for _, img := range result.Images {
+		if img.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", img.ImageName, img.Err))
+			continue
+		}
+		for _, node := range result.Nodes {
+			if r, ok := img.Nodes[node]; ok && r.Err != nil {
+				failures = append(failures, fmt.Sprintf("%s @ %s: %v", img.ImageName, node, r.Err))
+			}
+		}
+	}
+	return failures
+}
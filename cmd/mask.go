@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trynocoding/k8s-toolkit/internal/procinfo"
+)
+
+// splitNameArgs 将参数中逗号或空白分隔的名称展开为列表
+func splitNameArgs(args []string) []string {
+	var names []string
+	for _, arg := range args {
+		for _, name := range strings.FieldsFunc(arg, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		}) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// decodeMasks 解析命令行中的 mask 并输出解码结果
+// 文本格式每个 mask 一行，与 proc-status 的 "0x... -> NAME, NAME" 格式一致；
+// JSON/YAML 格式为 MaskDocument 数组
+func decodeMasks(args []string, format string, toDocument func(uint64) procinfo.MaskDocument) error {
+	if err := validateOutputFormat(format); err != nil {
+		return err
+	}
+
+	docs := make([]procinfo.MaskDocument, 0, len(args))
+	for _, arg := range args {
+		mask, err := procinfo.ParseMask(arg)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, toDocument(mask))
+	}
+
+	// 结构化输出总是数组，与参数顺序一致，只有一个 mask 时也不例外
+	if format != outputText {
+		return printStructured(format, docs)
+	}
+
+	for _, doc := range docs {
+		names := "<none>"
+		if len(doc.Names) > 0 {
+			names = strings.Join(doc.Names, ", ")
+		}
+		fmt.Printf("%s -> %s\n", doc.Mask, names)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// 结构化输出格式
+const (
+	outputText = ""
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+// validateOutputFormat 检查 -o 参数
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputText, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("无效的输出格式: %s (可选: json, yaml)", format)
+	}
+}
+
+// printStructured 以 JSON 或 YAML 格式输出到标准输出
+// YAML 由 JSON 转换而来，两种格式的字段名保持一致
+func printStructured(format string, v any) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputYAML:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode yaml: %w", err)
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	default:
+		return fmt.Errorf("无效的输出格式: %s (可选: json, yaml)", format)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trynocoding/k8s-toolkit/internal/kube"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// resolvePod 根据 -p 和 -l 参数确定目标 Pod
+// -p 可以是 Pod 名称或 deploy/NAME、sts/NAME 等工作负载引用；
+// 多个 Pod 处于 Running 状态时在终端中提示选择，非交互模式下报错并列出候选
+func resolvePod(ctx context.Context, client *kube.Client, namespace, ref, selector string) (*corev1.Pod, error) {
+	pods, selector, err := candidatePods(ctx, client, namespace, ref, selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 1 {
+		return &pods[0], nil
+	}
+	return promptPod(pods, selector)
+}
+
+// candidatePods 返回 -p 和 -l 参数匹配的 Pod 以及使用的标签选择器
+// -p 为 Pod 名称或指向单个 Pod 的工作负载时只有一个结果，选择器为空
+func candidatePods(ctx context.Context, client *kube.Client, namespace, ref, selector string) ([]corev1.Pod, string, error) {
+	if selector == "" {
+		name := ref
+		if kube.IsWorkloadRef(ref) {
+			sel, podName, err := client.WorkloadSelector(ctx, namespace, ref)
+			if err != nil {
+				return nil, "", err
+			}
+			name, selector = podName, sel
+		}
+		if name != "" {
+			pod, err := client.GetPod(ctx, namespace, name)
+			if err != nil {
+				return nil, "", err
+			}
+			return []corev1.Pod{*pod}, "", nil
+		}
+	}
+
+	pods, err := client.ListRunningPods(ctx, namespace, selector)
+	if err != nil {
+		return nil, "", err
+	}
+	return pods, selector, nil
+}
+
+// promptPod 列出候选 Pod 并读取用户选择的编号
+func promptPod(pods []corev1.Pod, selector string) (*corev1.Pod, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("匹配 '%s' 的 Running Pod 有 %d 个: %s，请使用 -p 指定",
+			selector, len(pods), strings.Join(podNames(pods), " "))
+	}
+
+	fmt.Fprintf(os.Stderr, "匹配 '%s' 的 Running Pod 有 %d 个:\n", selector, len(pods))
+	for i, p := range pods {
+		fmt.Fprintf(os.Stderr, "  %d) %-50s %-20s %s\n", i+1, p.Name, p.Spec.NodeName, podAge(&p))
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprintf(os.Stderr, "请选择 [1-%d]: ", len(pods))
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("读取选择失败: %w", err)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(line))
+		if err == nil && n >= 1 && n <= len(pods) {
+			return &pods[n-1], nil
+		}
+		fmt.Fprintf(os.Stderr, "无效的编号: %s\n", strings.TrimSpace(line))
+	}
+}
+
+// podDisplayRef 返回用于提示信息的 Pod 引用
+func podDisplayRef(ref, selector string) string {
+	if selector != "" {
+		return "-l " + selector
+	}
+	return ref
+}
+
+// podNames 返回 Pod 名称列表
+func podNames(pods []corev1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, p := range pods {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// podAge 返回 Pod 已启动的时间
+func podAge(pod *corev1.Pod) string {
+	if pod.Status.StartTime == nil {
+		return "-"
+	}
+	return time.Since(pod.Status.StartTime.Time).Round(time.Second).String()
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/trynocoding/k8s-toolkit/internal/procinfo"
+)
+
+// runProcStatusAudit 比较容器进程实际的 capability 与 Pod securityContext 推导出的集合
+// 存在差异时以退出码 1 结束，便于在 CI 中使用
+func runProcStatusAudit(ctx context.Context, source *procSource, parseOpts procinfo.ParseOptions) error {
+	if source.container == nil {
+		return fmt.Errorf("--audit requires a pod (use -p or -l)")
+	}
+
+	spec, err := capabilitySpecFor(source)
+	if err != nil {
+		return err
+	}
+	// privileged 和 add ALL 得到的是目标内核支持的全部 capability，而不是本工具已知的
+	if spec.LastCap, err = source.readLastCap(ctx); err != nil {
+		return err
+	}
+	expected, err := procinfo.ExpectedCapabilities(spec)
+	if err != nil {
+		return err
+	}
+	if source.verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Runtime default: 0x%016x, add: %v, drop: %v, privileged: %t, cap_last_cap: %d\n",
+			spec.Base, spec.Add, spec.Drop, spec.Privileged, spec.LastCap)
+	}
+
+	parseOpts.ParseCapabilities = true
+	status, err := source.readStatus(ctx, procPID, parseOpts)
+	if err != nil {
+		return err
+	}
+
+	result, err := procinfo.AuditCapabilities(status, expected)
+	if err != nil {
+		return err
+	}
+
+	if procOutput != outputText {
+		if err := printStructured(procOutput, result); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(procinfo.FormatAuditResult(result))
+	}
+
+	if !result.OK() {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// capabilitySpecFor 从容器的 securityContext 和运行时默认集合构造 CapabilitySpec
+// --audit-runtime 为 auto 时根据容器 ID 前缀 (containerd://、cri-o:// 等) 选择默认集合
+func capabilitySpecFor(source *procSource) (procinfo.CapabilitySpec, error) {
+	runtimeName := procAuditRuntime
+	if runtimeName == "" || runtimeName == "auto" {
+		runtimeName, _ = source.container.ContainerID()
+		if runtimeName == "" {
+			return procinfo.CapabilitySpec{}, fmt.Errorf("cannot detect container runtime of %s, use --audit-runtime", source)
+		}
+	}
+
+	base, err := procinfo.RuntimeDefaultCapabilities(runtimeName)
+	if err != nil {
+		return procinfo.CapabilitySpec{}, err
+	}
+
+	spec := procinfo.CapabilitySpec{Base: base}
+	if sc := source.container.SecurityContext(); sc != nil {
+		if sc.Privileged != nil {
+			spec.Privileged = *sc.Privileged
+		}
+		if sc.Capabilities != nil {
+			for _, c := range sc.Capabilities.Add {
+				spec.Add = append(spec.Add, string(c))
+			}
+			for _, c := range sc.Capabilities.Drop {
+				spec.Drop = append(spec.Drop, string(c))
+			}
+		}
+	}
+	return spec, nil
+}
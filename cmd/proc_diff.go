@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/trynocoding/k8s-toolkit/internal/kube"
+	"github.com/trynocoding/k8s-toolkit/internal/procinfo"
+	"sigs.k8s.io/yaml"
+)
+
+var procDiffCmd = &cobra.Command{
+	Use:   "diff (--pid A --pid B | SNAPSHOT_A SNAPSHOT_B) [OPTIONS]",
+	Short: "比较两个进程或两个快照的 Capabilities 和 Signals",
+	Long: `比较两个进程的 capability 集合和信号处理状态，列出每个集合中增加 (+) 和减少 (-) 的项。
+
+两个进程可以位于本机、同一个 Pod 或不同的 Pod 中；也可以比较两个
+proc-status -o json/yaml 保存的快照文件。差异以第一个进程为基准。
+
+示例:
+  # 比较本机两个进程
+  k8s-toolkit proc-status diff --pid 1234 --pid 5678
+
+  # 比较同一个 Pod 中主容器和 sidecar 的 1 号进程
+  k8s-toolkit proc-status diff -p my-pod --pid 1 --pid 1 --container-name app --container-name istio-proxy
+
+  # 比较两个 Pod 中的进程
+  k8s-toolkit proc-status diff -p pod-a -p pod-b --pid 1 --pid 1
+
+  # 比较两个快照
+  k8s-toolkit proc-status -p my-pod --pid 1 -o json > before.json
+  k8s-toolkit proc-status -p my-pod --pid 1 -o json > after.json
+  k8s-toolkit proc-status diff before.json after.json`,
+	RunE: runProcDiff,
+}
+
+var (
+	diffPIDs           []int
+	diffPods           []string
+	diffNamespace      string
+	diffContainerNames []string
+	diffOutput         string
+)
+
+func init() {
+	procStatusCmd.AddCommand(procDiffCmd)
+
+	procDiffCmd.Flags().IntSliceVar(&diffPIDs, "pid", nil,
+		"要比较的两个进程 PID (指定两次)")
+	procDiffCmd.Flags().StringArrayVarP(&diffPods, "pod", "p", nil,
+		"Pod 名称；指定一次时两个进程都在该 Pod 中，指定两次时分别对应两个进程")
+	procDiffCmd.Flags().StringVarP(&diffNamespace, "namespace", "n", "default",
+		"Kubernetes 命名空间 (默认: default)")
+	procDiffCmd.Flags().StringArrayVar(&diffContainerNames, "container-name", nil,
+		"容器名称；指定一次时用于两个进程，指定两次时分别对应两个进程 (默认: 第一个容器)")
+	procDiffCmd.Flags().StringVarP(&diffOutput, "output", "o", "",
+		"输出格式: json 或 yaml (默认: 可读文本)")
+
+	procDiffCmd.RegisterFlagCompletionFunc("pod",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			ns, _ := cmd.Flags().GetString("namespace")
+			pods, err := getPodNames(ns)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			return pods, cobra.ShellCompDirectiveNoFileComp
+		})
+}
+
+func runProcDiff(cmd *cobra.Command, args []string) error {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	if err := validateOutputFormat(diffOutput); err != nil {
+		return err
+	}
+
+	var left, right *procinfo.ProcessStatus
+	var leftLabel, rightLabel string
+	var err error
+
+	switch {
+	case len(args) == 2 && len(diffPIDs) == 0:
+		if left, err = loadSnapshot(args[0]); err != nil {
+			return err
+		}
+		if right, err = loadSnapshot(args[1]); err != nil {
+			return err
+		}
+		leftLabel = fmt.Sprintf("%s: pid %d (%s)", args[0], left.PID, left.Name)
+		rightLabel = fmt.Sprintf("%s: pid %d (%s)", args[1], right.PID, right.Name)
+	case len(args) == 0 && len(diffPIDs) == 2:
+		if len(diffPods) > 2 || len(diffContainerNames) > 2 {
+			return fmt.Errorf("--pod and --container-name can be given at most twice")
+		}
+		ctx := context.Background()
+		opts := procinfo.ParseOptions{ParseCapabilities: true, ParseSignals: true, Verbose: verbose}
+		if left, leftLabel, err = readDiffSide(ctx, 0, opts, verbose); err != nil {
+			return err
+		}
+		if right, rightLabel, err = readDiffSide(ctx, 1, opts, verbose); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("specify two processes with --pid A --pid B, or two snapshot files")
+	}
+
+	diff, err := procinfo.DiffStatus(left, right, leftLabel, rightLabel)
+	if err != nil {
+		return err
+	}
+	if diffOutput != outputText {
+		return printStructured(diffOutput, diff)
+	}
+	fmt.Println(procinfo.FormatStatusDiff(diff))
+	return nil
+}
+
+// readDiffSide 读取第 i 个进程的状态，--pod/--container-name 只给出一个时两侧共用
+func readDiffSide(ctx context.Context, i int, opts procinfo.ParseOptions, verbose bool) (*procinfo.ProcessStatus, string, error) {
+	sel := kube.ContainerSelector{Name: pickSide(diffContainerNames, i)}
+	source, err := newProcSource(ctx, pickSide(diffPods, i), "", diffNamespace, sel, verbose)
+	if err != nil {
+		return nil, "", err
+	}
+
+	status, err := source.readStatus(ctx, diffPIDs[i], opts)
+	if err != nil {
+		return nil, "", err
+	}
+	return status, fmt.Sprintf("pid %d (%s) @ %s", status.PID, status.Name, source), nil
+}
+
+// pickSide 返回第 i 个值；只有一个值时两侧共用
+func pickSide(values []string, i int) string {
+	switch len(values) {
+	case 0:
+		return ""
+	case 1:
+		return values[0]
+	default:
+		return values[i]
+	}
+}
+
+// loadSnapshot 读取 proc-status -o json/yaml 保存的快照
+func loadSnapshot(path string) (*procinfo.ProcessStatus, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var doc procinfo.StatusDocument
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s (expected the output of proc-status --pid N -o json|yaml): %w", path, err)
+	}
+
+	status, err := procinfo.FromDocument(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	return status, nil
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/trynocoding/k8s-toolkit/internal/kube"
+	"github.com/trynocoding/k8s-toolkit/internal/procinfo"
+)
+
+// errExecFailed 在容器中执行命令失败 (API Server 或容器运行时返回错误，而不是命令以非零退出码结束)
+var errExecFailed = errors.New("exec failed")
+
+// procSource 表示读取 /proc 的位置：本机，或通过 API Server exec 读取 Pod 容器
+type procSource struct {
+	client    *kube.Client
+	container *kube.Container // 为 nil 表示本机
+	verbose   bool
+}
+
+// newProcSource 根据 Pod 参数创建读取来源，未指定 Pod 时读取本机 /proc
+func newProcSource(ctx context.Context, podRef, selector, namespace string, sel kube.ContainerSelector, verbose bool) (*procSource, error) {
+	if podRef == "" && selector == "" {
+		return &procSource{verbose: verbose}, nil
+	}
+
+	client, err := newKubeClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// 与 enter-ns 使用同一个解析器选择容器，保证同样的参数总是选中同一个容器
+	pod, err := resolvePod(ctx, client, namespace, podRef, selector)
+	if err != nil {
+		return nil, err
+	}
+	container, err := kube.ResolveContainer(pod, sel)
+	if err != nil {
+		return nil, err
+	}
+	if err := container.CheckRunning(); err != nil {
+		return nil, err
+	}
+
+	return &procSource{client: client, container: container, verbose: verbose}, nil
+}
+
+// String 返回来源的描述
+func (s *procSource) String() string {
+	if s.container == nil {
+		return "localhost"
+	}
+	return fmt.Sprintf("pod %s/%s (container: %s)", s.container.Pod.Namespace, s.container.Pod.Name, s.container.Name)
+}
+
+// readStatus 读取并解析单个进程的状态
+func (s *procSource) readStatus(ctx context.Context, pid int, opts procinfo.ParseOptions) (*procinfo.ProcessStatus, error) {
+	if s.container == nil {
+		status, err := procinfo.ParseProcStatus(pid, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse /proc/%d/status: %w", pid, err)
+		}
+		return status, nil
+	}
+
+	if s.verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Fetching /proc/%d/status from %s\n", pid, s)
+	}
+	content, err := s.exec(ctx, []string{"cat", fmt.Sprintf("/proc/%d/status", pid)})
+	if err != nil {
+		// cat 以非零退出码结束说明进程不存在
+		var exitErr *kube.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("%w: %d in %s", procinfo.ErrProcessNotFound, pid, s)
+		}
+		return nil, fmt.Errorf("failed to read process status in pod: %w", err)
+	}
+
+	status, err := procinfo.ParseStatus(bytes.NewReader(content), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proc status: %w", err)
+	}
+	if status.PID == 0 {
+		status.PID = pid
+	}
+	return status, nil
+}
+
+// readLastCap 读取目标内核的 cap_last_cap
+func (s *procSource) readLastCap(ctx context.Context) (int, error) {
+	var content []byte
+	var err error
+	if s.container == nil {
+		content, err = os.ReadFile(procinfo.CapLastCapPath)
+	} else {
+		content, err = s.exec(ctx, []string{"cat", procinfo.CapLastCapPath})
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s from %s: %w", procinfo.CapLastCapPath, s, err)
+	}
+	return procinfo.ParseLastCap(string(content))
+}
+
+// listStatus 读取并解析所有进程的状态
+func (s *procSource) listStatus(ctx context.Context, opts procinfo.ParseOptions) ([]*procinfo.ProcessStatus, error) {
+	if s.container == nil {
+		return procinfo.ListProcStatus(opts)
+	}
+
+	if s.verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Fetching /proc/*/status from %s\n", s)
+	}
+	statuses, err := s.catStatus(ctx, "/proc/[0-9]*/status", opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes in pod (the container needs sh and cat): %w", err)
+	}
+	return statuses, nil
+}
+
+// listThreads 读取并解析进程所有线程的状态，返回的 PID 字段是 TID
+func (s *procSource) listThreads(ctx context.Context, pid int, opts procinfo.ParseOptions) ([]*procinfo.ProcessStatus, error) {
+	if s.container == nil {
+		return procinfo.ListThreadStatus(pid, opts)
+	}
+
+	if s.verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Fetching /proc/%d/task/*/status from %s\n", pid, s)
+	}
+	threads, err := s.catStatus(ctx, fmt.Sprintf("/proc/%d/task/[0-9]*/status", pid), opts)
+	var exitErr *kube.ExitError
+	if errors.As(err, &exitErr) || (err == nil && len(threads) == 0) {
+		// cat 没有读到任何内容说明进程不存在
+		return nil, fmt.Errorf("%w: %d in %s", procinfo.ErrProcessNotFound, pid, s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list threads in pod (the container needs sh and cat): %w", err)
+	}
+	return threads, nil
+}
+
+// catStatus 在容器中用一次 cat 读取匹配 pattern 的所有 status 文件并逐个解析
+// 遍历期间退出的进程或线程会让 cat 返回非零退出码，此时仍使用已读取的内容
+func (s *procSource) catStatus(ctx context.Context, pattern string, opts procinfo.ParseOptions) ([]*procinfo.ProcessStatus, error) {
+	content, err := s.exec(ctx, []string{"sh", "-c", "cat " + pattern + " 2>/dev/null"})
+	var exitErr *kube.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && len(content) > 0) {
+		return nil, err
+	}
+
+	return procinfo.ParseStatusContent(bytes.NewReader(content), opts)
+}
+
+// exec 在容器中执行命令并返回标准输出
+// 命令以非零退出码结束时同时返回已读取的输出和 *kube.ExitError
+func (s *procSource) exec(ctx context.Context, command []string) ([]byte, error) {
+	if s.verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Executing: %s\n", strings.Join(command, " "))
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := s.client.Exec(ctx, kube.ExecOptions{
+		Namespace: s.container.Pod.Namespace,
+		Pod:       s.container.Pod.Name,
+		Container: s.container.Name,
+		Command:   command,
+		Stdout:    &stdout,
+		Stderr:    &stderr,
+	})
+	if err != nil {
+		if stderr.Len() > 0 {
+			return stdout.Bytes(), fmt.Errorf("%w: %w\nStderr: %s", errExecFailed, err, stderr.String())
+		}
+		return stdout.Bytes(), fmt.Errorf("%w: %w", errExecFailed, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// containerStopped 重新获取 Pod，返回容器已停止或已重启的原因；容器仍在运行时返回空字符串
+// 本机来源总是返回空字符串
+func (s *procSource) containerStopped(ctx context.Context) string {
+	if s.container == nil {
+		return ""
+	}
+
+	pod, err := s.client.GetPod(ctx, s.container.Pod.Namespace, s.container.Pod.Name)
+	if err != nil {
+		return err.Error()
+	}
+	container, err := kube.ResolveContainer(pod, kube.ContainerSelector{Name: s.container.Name})
+	if err != nil {
+		return err.Error()
+	}
+	if err := container.CheckRunning(); err != nil {
+		return err.Error()
+	}
+	if _, id := container.ContainerID(); id != "" {
+		if _, oldID := s.container.ContainerID(); oldID != "" && id != oldID {
+			return fmt.Sprintf("container '%s' restarted", container.Name)
+		}
+	}
+	return ""
+}
@@ -1,22 +1,21 @@
 package cmd
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
-	"strconv"
-	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/trynocoding/k8s-toolkit/internal/kube"
 	"github.com/trynocoding/k8s-toolkit/internal/procinfo"
 )
 
 var procStatusCmd = &cobra.Command{
-	Use:   "proc-status -p PID [OPTIONS]",
+	Use:   "proc-status (--pid PID | --all | --tree) [OPTIONS]",
 	Short: "查看进程的 Capabilities 和 Signals 信息",
 	Long: `查看进程的 Linux Capabilities 和 Signals 信息，自动解码为可读格式。
 
-可以直接查看本地进程，或通过 kubectl exec 查看 Pod 容器内的进程。
+可以直接查看本地进程，或通过 API Server exec（与 kubectl exec 相同）查看 Pod 容器内的进程。
 
 示例:
   # 查看本地进程 (PID 1234)
@@ -34,6 +33,40 @@ var procStatusCmd = &cobra.Command{
   # 查看指定容器的进程
   k8s-toolkit proc-status -p my-pod -n kube-system -c 1 --pid 1
 
+  # 按标签或工作负载选择 Pod
+  k8s-toolkit proc-status -l app=nginx --pid 1
+  k8s-toolkit proc-status -p deploy/nginx --pid 1
+
+  # 按名称选择容器（也可以是 init 容器或 ephemeral 调试容器）
+  k8s-toolkit proc-status -p my-pod --container-name sidecar --pid 1
+
+  # 只查看 seccomp/no_new_privs 和内存
+  k8s-toolkit proc-status --pid 1234 --section security,memory
+
+  # 审计容器主进程的 capability 是否与 securityContext 一致
+  k8s-toolkit proc-status -p my-pod --pid 1 --audit
+
+  # 监视信号状态的变化，排查优雅退出卡住的问题
+  k8s-toolkit proc-status -p my-pod --pid 1 --watch --interval 200ms
+
+  # 按线程查看信号 mask，找出阻塞 SIGTERM 的工作线程
+  k8s-toolkit proc-status -p my-pod --pid 1 --threads
+
+  # 以 JSON 输出，便于 jq 等工具处理
+  k8s-toolkit proc-status --pid 1234 -o json | jq -r '.capabilities.effective.names[]'
+
+  # 列出本机所有进程
+  k8s-toolkit proc-status --all
+
+  # 列出 Pod 容器内所有进程，只显示有 CAP_NET_ADMIN 的 root 进程
+  k8s-toolkit proc-status -p my-pod --all --uid 0 --has-cap NET_ADMIN
+
+  # 显示 Pod 容器内的进程树，查找 setuid 或文件 capability 导致的提权
+  k8s-toolkit proc-status -p my-pod --tree
+
+  # 按进程名过滤
+  k8s-toolkit proc-status --all --name nginx --capabilities
+
   # 详细模式
   k8s-toolkit proc-status --pid 1234 -v`,
 	RunE: runProcStatus,
@@ -42,33 +75,101 @@ var procStatusCmd = &cobra.Command{
 var (
 	procPID              int
 	procPodName          string
+	procPodSelector      string
 	procNamespace        string
 	procContainerIndex   int
+	procContainerName    string
 	procShowCapabilities bool
 	procShowSignals      bool
+	procAll              bool
+	procFilterName       string
+	procFilterUID        int
+	procFilterCaps       []string
+	procOutput           string
+	procSections         []string
+	procAudit            bool
+	procAuditRuntime     string
+	procWatch            bool
+	procWatchInterval    time.Duration
+	procThreads          bool
+	procThreadSignal     string
+	procTree             bool
 )
 
 func init() {
 	rootCmd.AddCommand(procStatusCmd)
 
-	// PID 参数 (必需)
+	// PID 参数 (与 --all 二选一)
 	procStatusCmd.Flags().IntVar(&procPID, "pid", 0,
-		"进程 PID (必需)")
-	procStatusCmd.MarkFlagRequired("pid")
+		"进程 PID (必需，除非使用 --all 或 --tree；与 --tree 一起使用时只显示该进程的子树)")
+	procStatusCmd.Flags().BoolVar(&procAll, "all", false,
+		"列出所有进程 (本机或 Pod 容器内)，以表格输出")
+	procStatusCmd.MarkFlagsMutuallyExclusive("pid", "all")
 
 	// Pod 相关参数 (可选，用于查看 Pod 内进程)
 	procStatusCmd.Flags().StringVarP(&procPodName, "pod", "p", "",
-		"Pod 名称 (可选，用于查看 Pod 内进程)")
+		"Pod 名称，或 deploy/NAME、sts/NAME 等工作负载引用 (可选，用于查看 Pod 内进程)")
+	procStatusCmd.Flags().StringVarP(&procPodSelector, "selector", "l", "",
+		"Pod 标签选择器，如 app=foo (与 --pod 互斥)")
+	procStatusCmd.MarkFlagsMutuallyExclusive("pod", "selector")
 	procStatusCmd.Flags().StringVarP(&procNamespace, "namespace", "n", "default",
 		"Kubernetes 命名空间 (默认: default)")
 	procStatusCmd.Flags().IntVarP(&procContainerIndex, "container", "c", 0,
-		"容器索引 (默认: 0，即第一个容器)")
+		"容器索引，按 spec.containers 声明顺序 (默认: 0，即第一个容器)")
+	procStatusCmd.Flags().StringVar(&procContainerName, "container-name", "",
+		"容器名称，支持 init 容器和 ephemeral 调试容器 (与 --container 互斥)")
+	procStatusCmd.MarkFlagsMutuallyExclusive("container", "container-name")
 
 	// 过滤选项
 	procStatusCmd.Flags().BoolVar(&procShowCapabilities, "capabilities", false,
 		"只显示 Capabilities 信息")
 	procStatusCmd.Flags().BoolVar(&procShowSignals, "signals", false,
 		"只显示 Signals 信息")
+	procStatusCmd.Flags().StringSliceVar(&procSections, "section", nil,
+		"要显示的部分，逗号分隔 (identity,capabilities,signals,memory,security,scheduling，默认: 全部)")
+
+	procStatusCmd.Flags().StringVarP(&procOutput, "output", "o", "",
+		"输出格式: json 或 yaml (默认: 可读文本)")
+
+	// capability 审计
+	procStatusCmd.Flags().BoolVar(&procAudit, "audit", false,
+		"比较进程的 CapEff/CapBnd 与 Pod securityContext 推导出的集合，有差异时退出码为 1")
+	procStatusCmd.Flags().StringVar(&procAuditRuntime, "audit-runtime", "auto",
+		"--audit 使用的运行时默认 capability 集合 (auto|containerd|docker|cri-o)")
+	procStatusCmd.MarkFlagsMutuallyExclusive("audit", "all")
+
+	// 信号状态监视
+	procStatusCmd.Flags().BoolVar(&procWatch, "watch", false,
+		"持续监视进程，只输出 pending/blocked/ignored/caught 信号的变化，进程退出时给出解释")
+	procStatusCmd.Flags().DurationVar(&procWatchInterval, "interval", 200*time.Millisecond,
+		"--watch 的轮询间隔 (如 200ms, 1s)")
+	procStatusCmd.MarkFlagsMutuallyExclusive("watch", "all")
+	procStatusCmd.MarkFlagsMutuallyExclusive("watch", "audit")
+
+	// 线程级状态
+	procStatusCmd.Flags().BoolVar(&procThreads, "threads", false,
+		"遍历 /proc/<pid>/task，按 SigPnd/SigBlk/CapEff 对线程分组，并标出接收进程级信号的线程")
+	procStatusCmd.Flags().StringVar(&procThreadSignal, "thread-signal", "SIGTERM",
+		"--threads 推断接收线程时使用的信号")
+	procStatusCmd.MarkFlagsMutuallyExclusive("threads", "all")
+	procStatusCmd.MarkFlagsMutuallyExclusive("threads", "audit")
+	procStatusCmd.MarkFlagsMutuallyExclusive("threads", "watch")
+
+	// 进程树
+	procStatusCmd.Flags().BoolVar(&procTree, "tree", false,
+		"按 PPid 显示进程树，标注 uid、CapEff、NoNewPrivs，并标出相对父进程获得额外权限的进程 (可用 --pid 指定子树的根)")
+	procStatusCmd.MarkFlagsMutuallyExclusive("tree", "all")
+	procStatusCmd.MarkFlagsMutuallyExclusive("tree", "audit")
+	procStatusCmd.MarkFlagsMutuallyExclusive("tree", "watch")
+	procStatusCmd.MarkFlagsMutuallyExclusive("tree", "threads")
+
+	// --all 的进程过滤条件
+	procStatusCmd.Flags().StringVar(&procFilterName, "name", "",
+		"只显示进程名包含该字符串的进程 (用于 --all)")
+	procStatusCmd.Flags().IntVar(&procFilterUID, "uid", -1,
+		"只显示有效 UID 等于该值的进程 (用于 --all)")
+	procStatusCmd.Flags().StringSliceVar(&procFilterCaps, "has-cap", nil,
+		"只显示 CapEff 中包含这些 capability 的进程，如 NET_ADMIN (用于 --all)")
 
 	// 注册补全函数
 	registerProcStatusCompletions()
@@ -101,252 +202,138 @@ func registerProcStatusCompletions() {
 			}
 			return pods, cobra.ShellCompDirectiveNoFileComp
 		})
+
+	// container-name 补全（根据当前 pod 的 spec）
+	procStatusCmd.RegisterFlagCompletionFunc("container-name", completeContainerNames)
+
+	// section 补全
+	procStatusCmd.RegisterFlagCompletionFunc("section",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return procinfo.AllSections, cobra.ShellCompDirectiveNoFileComp
+		})
+
+	// 审计运行时补全
+	procStatusCmd.RegisterFlagCompletionFunc("audit-runtime",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"auto", "containerd", "docker", "cri-o"}, cobra.ShellCompDirectiveNoFileComp
+		})
+
+	// 输出格式补全
+	procStatusCmd.RegisterFlagCompletionFunc("output",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"json", "yaml"}, cobra.ShellCompDirectiveNoFileComp
+		})
 }
 
 func runProcStatus(cmd *cobra.Command, args []string) error {
 	verbose, _ := cmd.Flags().GetBool("verbose")
 
 	// 验证 PID
-	if procPID <= 0 {
-		return fmt.Errorf("invalid PID: %d", procPID)
+	if !procAll && !(procTree && procPID == 0) && procPID <= 0 {
+		return fmt.Errorf("invalid PID: %d (use --pid, --all or --tree)", procPID)
 	}
-
-	// 确定要解析的内容
-	parseOpts := procinfo.ParseOptions{
-		ParseCapabilities: true,
-		ParseSignals:      true,
-		Verbose:           verbose,
+	if err := validateOutputFormat(procOutput); err != nil {
+		return err
 	}
 
-	// 如果指定了过滤选项，只解析对应的内容
-	if procShowCapabilities && !procShowSignals {
-		parseOpts.ParseSignals = false
-	} else if procShowSignals && !procShowCapabilities {
-		parseOpts.ParseCapabilities = false
+	// 确定要解析的内容: --capabilities/--signals 等同于对应的 --section，都不指定时解析所有部分
+	sections := procSections
+	if procShowCapabilities {
+		sections = append(sections, procinfo.SectionCapabilities)
 	}
-
-	var status *procinfo.ProcessStatus
-	var err error
-
-	// 判断是查看本地进程还是 Pod 内进程
-	if procPodName == "" {
-		// 查看本地进程
-		status, err = procinfo.ParseProcStatus(procPID, parseOpts)
-		if err != nil {
-			return fmt.Errorf("failed to parse /proc/%d/status: %w", procPID, err)
-		}
-	} else {
-		// 通过 kubectl exec 查看 Pod 内进程
-		status, err = parseProcStatusInPod(procPodName, procNamespace, procContainerIndex, procPID, parseOpts, verbose)
-		if err != nil {
-			return fmt.Errorf("failed to parse process status in pod: %w", err)
-		}
+	if procShowSignals {
+		sections = append(sections, procinfo.SectionSignals)
 	}
-
-	// 输出结果
-	output := procinfo.FormatProcessStatus(status, parseOpts)
-	fmt.Println(output)
-
-	return nil
-}
-
-// parseProcStatusInPod 通过 kubectl exec 解析 Pod 内进程的状态
-func parseProcStatusInPod(podName, namespace string, containerIndex, pid int, opts procinfo.ParseOptions, verbose bool) (*procinfo.ProcessStatus, error) {
-	if verbose {
-		fmt.Printf("[DEBUG] Fetching /proc/%d/status from pod %s/%s (container index: %d)\n",
-			pid, namespace, podName, containerIndex)
+	parseOpts, err := procinfo.OptionsForSections(sections)
+	if err != nil {
+		return err
 	}
+	parseOpts.Verbose = verbose
 
-	// 构建 kubectl exec 命令
-	args := []string{
-		"exec",
-		podName,
-		"-n", namespace,
-		"--container", fmt.Sprintf("$(%s)", getContainerNameCommand(containerIndex)),
-		"--",
-		"cat", fmt.Sprintf("/proc/%d/status", pid),
+	// 判断是查看本地进程还是 Pod 内进程
+	ctx := context.Background()
+	sel := kube.ContainerSelector{Name: procContainerName, Index: procContainerIndex}
+	source, err := newProcSource(ctx, procPodName, procPodSelector, procNamespace, sel, verbose)
+	if err != nil {
+		return err
 	}
 
-	// 如果容器索引为 0，简化命令（不指定容器）
-	if containerIndex == 0 {
-		args = []string{
-			"exec",
-			podName,
-			"-n", namespace,
-			"--",
-			"cat", fmt.Sprintf("/proc/%d/status", pid),
-		}
-	} else {
-		// 需要先获取容器名称
-		containerName, err := getContainerName(podName, namespace, containerIndex)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get container name: %w", err)
-		}
-		args = []string{
-			"exec",
-			podName,
-			"-n", namespace,
-			"--container", containerName,
-			"--",
-			"cat", fmt.Sprintf("/proc/%d/status", pid),
-		}
+	if procAll {
+		return runProcStatusAll(ctx, source, parseOpts)
 	}
-
-	// 执行 kubectl exec
-	kubectlCmd := exec.Command("kubectl", args...)
-	var stdout, stderr bytes.Buffer
-	kubectlCmd.Stdout = &stdout
-	kubectlCmd.Stderr = &stderr
-
-	if verbose {
-		fmt.Printf("[DEBUG] Executing: kubectl %s\n", strings.Join(args, " "))
+	if procTree {
+		return runProcStatusTree(ctx, source, parseOpts)
 	}
-
-	if err := kubectlCmd.Run(); err != nil {
-		return nil, fmt.Errorf("kubectl exec failed: %w\nStderr: %s", err, stderr.String())
+	if procAudit {
+		return runProcStatusAudit(ctx, source, parseOpts)
 	}
-
-	// 解析输出
-	status, err := parseProcStatusFromContent(stdout.Bytes(), pid, opts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse proc status: %w", err)
+	if procWatch {
+		return runProcStatusWatch(ctx, source, parseOpts)
 	}
-
-	return status, nil
-}
-
-// getContainerName 获取 Pod 中指定索引的容器名称
-func getContainerName(podName, namespace string, containerIndex int) (string, error) {
-	args := []string{
-		"get", "pod", podName,
-		"-n", namespace,
-		"-o", fmt.Sprintf("jsonpath={.spec.containers[%d].name}", containerIndex),
+	if procThreads {
+		return runProcStatusThreads(ctx, source, parseOpts)
 	}
 
-	cmd := exec.Command("kubectl", args...)
-	output, err := cmd.Output()
+	status, err := source.readStatus(ctx, procPID, parseOpts)
 	if err != nil {
-		return "", fmt.Errorf("failed to get container name: %w", err)
+		return err
 	}
 
-	containerName := strings.TrimSpace(string(output))
-	if containerName == "" {
-		return "", fmt.Errorf("container index %d not found", containerIndex)
+	// 输出结果
+	if procOutput != outputText {
+		return printStructured(procOutput, procinfo.ToDocument(status))
 	}
+	output := procinfo.FormatProcessStatus(status, parseOpts)
+	fmt.Println(output)
 
-	return containerName, nil
-}
-
-// getContainerNameCommand 返回获取容器名称的命令
-func getContainerNameCommand(containerIndex int) string {
-	return fmt.Sprintf("kubectl get pod $POD_NAME -n $NAMESPACE -o jsonpath='{.spec.containers[%d].name}'", containerIndex)
+	return nil
 }
 
-// parseProcStatusFromContent 从内容中解析进程状态
-func parseProcStatusFromContent(content []byte, pid int, opts procinfo.ParseOptions) (*procinfo.ProcessStatus, error) {
-	status := &procinfo.ProcessStatus{
-		PID: pid,
+// runProcStatusAll 列出所有进程并按过滤条件输出表格
+func runProcStatusAll(ctx context.Context, source *procSource, parseOpts procinfo.ParseOptions) error {
+	filter := procinfo.ProcessFilter{
+		Name:         procFilterName,
+		UID:          procFilterUID,
+		Capabilities: procFilterCaps,
 	}
-
-	if opts.ParseCapabilities {
-		status.Capabilities = &procinfo.CapabilitiesInfo{}
+	for _, name := range filter.Capabilities {
+		if _, err := procinfo.CapabilityBit(name); err != nil {
+			return err
+		}
 	}
-	if opts.ParseSignals {
-		status.Signals = &procinfo.SignalsInfo{}
+	// 按 capability 过滤时需要解析 Capabilities
+	listOpts := parseOpts
+	if len(filter.Capabilities) > 0 {
+		listOpts.ParseCapabilities = true
 	}
 
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		fields := strings.SplitN(line, ":", 2)
-		if len(fields) != 2 {
-			continue
-		}
-
-		key := strings.TrimSpace(fields[0])
-		value := strings.TrimSpace(fields[1])
-
-		switch key {
-		case "Name":
-			status.Name = value
-		case "State":
-			status.State = value
-
-		// Capabilities
-		case "CapInh":
-			if status.Capabilities != nil {
-				if mask, err := parseHexUint64(value); err == nil {
-					status.Capabilities.Inheritable = mask
-				}
-			}
-		case "CapPrm":
-			if status.Capabilities != nil {
-				if mask, err := parseHexUint64(value); err == nil {
-					status.Capabilities.Permitted = mask
-				}
-			}
-		case "CapEff":
-			if status.Capabilities != nil {
-				if mask, err := parseHexUint64(value); err == nil {
-					status.Capabilities.Effective = mask
-				}
-			}
-		case "CapBnd":
-			if status.Capabilities != nil {
-				if mask, err := parseHexUint64(value); err == nil {
-					status.Capabilities.Bounding = mask
-				}
-			}
-		case "CapAmb":
-			if status.Capabilities != nil {
-				if mask, err := parseHexUint64(value); err == nil {
-					status.Capabilities.Ambient = mask
-				}
-			}
+	statuses, err := source.listStatus(ctx, listOpts)
+	if err != nil {
+		return err
+	}
 
-		// Signals
-		case "SigQ":
-			if status.Signals != nil {
-				status.Signals.Queued = value
-			}
-		case "SigPnd":
-			if status.Signals != nil {
-				if mask, err := parseHexUint64(value); err == nil {
-					status.Signals.Pending = mask
-				}
-			}
-		case "ShdPnd":
-			if status.Signals != nil {
-				if mask, err := parseHexUint64(value); err == nil {
-					status.Signals.SharedPending = mask
-				}
-			}
-		case "SigBlk":
-			if status.Signals != nil {
-				if mask, err := parseHexUint64(value); err == nil {
-					status.Signals.Blocked = mask
-				}
-			}
-		case "SigIgn":
-			if status.Signals != nil {
-				if mask, err := parseHexUint64(value); err == nil {
-					status.Signals.Ignored = mask
-				}
-			}
-		case "SigCgt":
-			if status.Signals != nil {
-				if mask, err := parseHexUint64(value); err == nil {
-					status.Signals.Caught = mask
-				}
-			}
+	var matched []*procinfo.ProcessStatus
+	for _, status := range statuses {
+		ok, err := filter.Match(status)
+		if err != nil {
+			return err
+		}
+		if ok {
+			matched = append(matched, status)
 		}
 	}
+	if procOutput != outputText {
+		docs := make([]*procinfo.StatusDocument, 0, len(matched))
+		for _, status := range matched {
+			docs = append(docs, procinfo.ToDocument(status))
+		}
+		return printStructured(procOutput, docs)
+	}
+	if len(matched) == 0 {
+		fmt.Printf("No process matched (%d processes scanned)\n", len(statuses))
+		return nil
+	}
 
-	return status, nil
-}
-
-// parseHexUint64 解析十六进制字符串为 uint64
-func parseHexUint64(s string) (uint64, error) {
-	s = strings.TrimPrefix(s, "0x")
-	s = strings.TrimSpace(s)
-	return strconv.ParseUint(s, 16, 64)
+	fmt.Println(procinfo.FormatProcessTable(matched, parseOpts))
+	return nil
 }
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/trynocoding/k8s-toolkit/internal/procinfo"
+)
+
+// runProcStatusThreads 读取进程所有线程的状态，按线程私有的 mask 分组输出
+// 并推断进程级信号 (--thread-signal) 会由哪个线程接收
+func runProcStatusThreads(ctx context.Context, source *procSource, parseOpts procinfo.ParseOptions) error {
+	signum, err := procinfo.SignalNumber(procThreadSignal)
+	if err != nil {
+		return err
+	}
+
+	// 分组依赖 SigPnd/SigBlk
+	parseOpts.ParseSignals = true
+	threads, err := source.listThreads(ctx, procPID, parseOpts)
+	if err != nil {
+		return err
+	}
+
+	name := threads[0].Name
+	for _, t := range threads {
+		if t.PID == procPID {
+			name = t.Name
+			break
+		}
+	}
+	groups := procinfo.GroupThreads(threads)
+	recipient := procinfo.ProcessSignalRecipient(procPID, threads, signum)
+
+	if procOutput != outputText {
+		return printStructured(procOutput, procinfo.ThreadsToDocument(procPID, name, threads, groups, recipient))
+	}
+	fmt.Println(procinfo.FormatThreadGroups(procPID, name, threads, groups, recipient, parseOpts))
+	return nil
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/trynocoding/k8s-toolkit/internal/procinfo"
+)
+
+// runProcStatusTree 按 PPid 输出进程树，标出相对父进程获得额外权限的进程
+// 指定 --pid 时只输出以该进程为根的子树
+func runProcStatusTree(ctx context.Context, source *procSource, parseOpts procinfo.ParseOptions) error {
+	// 树的标注需要 CapEff 和 NoNewPrivs
+	parseOpts.ParseCapabilities = true
+	parseOpts.ParseSecurity = true
+	statuses, err := source.listStatus(ctx, parseOpts)
+	if err != nil {
+		return err
+	}
+
+	roots := procinfo.BuildProcessTree(statuses)
+	if procPID > 0 {
+		node := procinfo.FindProcessNode(roots, procPID)
+		if node == nil {
+			return fmt.Errorf("%w: %d in %s", procinfo.ErrProcessNotFound, procPID, source)
+		}
+		roots = []*procinfo.ProcessNode{node}
+	}
+
+	if procOutput != outputText {
+		return printStructured(procOutput, procinfo.TreeToDocument(roots))
+	}
+	fmt.Println(procinfo.FormatProcessTree(roots))
+	return nil
+}
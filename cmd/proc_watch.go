@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/trynocoding/k8s-toolkit/internal/procinfo"
+)
+
+// watchTimeFormat watch 输出的时间戳格式
+const watchTimeFormat = "15:04:05.000"
+
+// runProcStatusWatch 按间隔轮询进程状态，只输出信号集合的变化
+// 进程退出时根据最后一次采样解释 SIGTERM 的处理情况
+func runProcStatusWatch(ctx context.Context, source *procSource, parseOpts procinfo.ParseOptions) error {
+	if procWatchInterval <= 0 {
+		return fmt.Errorf("invalid --interval: %s", procWatchInterval)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	parseOpts.ParseSignals = true
+	last, err := source.readStatus(ctx, procPID, parseOpts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s watching pid %d (%s) @ %s every %s, Ctrl-C to stop\n",
+		time.Now().Format(watchTimeFormat), last.PID, last.Name, source, procWatchInterval)
+	fmt.Printf("%s SigPnd: %s\n", strings.Repeat(" ", len(watchTimeFormat)), procinfo.FormatSignalMask(last.Signals.Pending))
+	fmt.Printf("%s ShdPnd: %s\n", strings.Repeat(" ", len(watchTimeFormat)), procinfo.FormatSignalMask(last.Signals.SharedPending))
+	fmt.Printf("%s SigBlk: %s\n", strings.Repeat(" ", len(watchTimeFormat)), procinfo.FormatSignalMask(last.Signals.Blocked))
+	fmt.Printf("%s SigIgn: %s\n", strings.Repeat(" ", len(watchTimeFormat)), procinfo.FormatSignalMask(last.Signals.Ignored))
+	fmt.Printf("%s SigCgt: %s\n", strings.Repeat(" ", len(watchTimeFormat)), procinfo.FormatSignalMask(last.Signals.Caught))
+
+	ticker := time.NewTicker(procWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		status, err := source.readStatus(ctx, procPID, parseOpts)
+		now := time.Now().Format(watchTimeFormat)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// PID 1 退出时容器随之停止，之后的 exec 以传输错误或容器不存在失败，
+			// 已经成功采样过，因此同样视为进程退出
+			if errors.Is(err, errExecFailed) {
+				reason := source.containerStopped(ctx)
+				if reason == "" {
+					reason = err.Error()
+				}
+				fmt.Printf("%s process %d exited (%s)\n", now, procPID, reason)
+				printExitExplanation(last)
+				return nil
+			}
+			if errors.Is(err, procinfo.ErrProcessNotFound) {
+				fmt.Printf("%s process %d exited\n", now, procPID)
+				printExitExplanation(last)
+				return nil
+			}
+			return err
+		}
+
+		if status.State != last.State && strings.HasPrefix(status.State, "Z") {
+			fmt.Printf("%s state %s\n", now, status.State)
+		}
+		diffs, err := procinfo.DiffSignals(last.Signals, status.Signals)
+		if err != nil {
+			return err
+		}
+		for _, d := range diffs {
+			fmt.Printf("%s %s\n", now, procinfo.FormatSignalTransition(d))
+		}
+		last = status
+	}
+}
+
+// printExitExplanation 根据最后一次采样输出进程退出的解释
+func printExitExplanation(last *procinfo.ProcessStatus) {
+	for _, line := range procinfo.ExplainExit(last) {
+		fmt.Printf("%s   %s\n", strings.Repeat(" ", len(watchTimeFormat)), line)
+	}
+}
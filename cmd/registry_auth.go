@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/trynocoding/k8s-toolkit/internal/imgsync"
+)
+
+// loadRegistryCredentials 根据 --registry/--registry-user/--registry-password-stdin 和 docker 配置文件加载镜像仓库凭据
+// 与 docker login --password-stdin 相同，密码从标准输入读取，避免出现在命令行和 shell 历史中
+// 显式凭据只发送给 registry 指定的仓库，registry 为空时使用 image 所在的仓库
+func loadRegistryCredentials(registry, user string, passwordStdin bool, image string) (*imgsync.Credentials, error) {
+	if passwordStdin && user == "" {
+		return nil, fmt.Errorf("--registry-password-stdin 需要同时指定 --registry-user")
+	}
+	if user != "" && !passwordStdin {
+		return nil, fmt.Errorf("--registry-user 需要通过 --registry-password-stdin 提供密码")
+	}
+	if registry != "" && user == "" {
+		return nil, fmt.Errorf("--registry 需要同时指定 --registry-user")
+	}
+	if user != "" && registry == "" {
+		if image == "" {
+			return nil, fmt.Errorf("使用 -f 批量同步时需要通过 --registry 指定 --registry-user 对应的镜像仓库")
+		}
+		var err error
+		if registry, err = imgsync.RegistryHost(image); err != nil {
+			return nil, err
+		}
+	}
+
+	var password string
+	if passwordStdin {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("从标准输入读取密码失败: %w", err)
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+		if password == "" {
+			return nil, fmt.Errorf("标准输入中的密码为空")
+		}
+	}
+
+	return imgsync.LoadCredentials(registry, user, password)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/trynocoding/k8s-toolkit/internal/kube"
 )
 
 var (
@@ -14,13 +15,21 @@ var (
 	GitCommit = "unknown"
 )
 
+var (
+	// Kubernetes 连接参数
+	kubeconfig  string
+	kubeContext string
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "k8s-toolkit",
 	Short: "Kubernetes工具集 - 集成常用K8s运维脚本",
 	Long: `k8s-toolkit 是一个用Go编写的Kubernetes运维工具集。
 	
 它整合了多个常用的bash脚本，提供统一的命令行接口：
-- enter-ns: 进入Pod的网络命名空间
+- enter-ns: 进入Pod的命名空间
+- proc-status: 查看进程的 Capabilities 和 Signals 信息
+- caps / signals: 解码和编码 capability、信号 mask
 - img-sync: Docker镜像同步和分发工具
 - fcp: 文件并行分发到多节点
 - multi-exec: 多节点并行命令执行
@@ -44,4 +53,16 @@ func Execute() {
 func init() {
 	// 全局标志
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "详细输出模式")
+	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "",
+		"kubeconfig 文件路径 (默认: $KUBECONFIG 或 ~/.kube/config)")
+	rootCmd.PersistentFlags().StringVar(&kubeContext, "context", "",
+		"使用的 kubeconfig 上下文 (默认: current-context)")
+}
+
+// newKubeClient 根据 --kubeconfig 和 --context 创建 Kubernetes 客户端
+func newKubeClient() (*kube.Client, error) {
+	return kube.NewClient(kube.ClientOptions{
+		Kubeconfig: kubeconfig,
+		Context:    kubeContext,
+	})
 }
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/trynocoding/k8s-toolkit/internal/procinfo"
+)
+
+var signalsCmd = &cobra.Command{
+	Use:   "signals",
+	Short: "解码和编码信号 mask",
+	Long: `在没有运行中进程的情况下解码或编码信号 bitmask (SigBlk/SigIgn/SigCgt 等)。
+
+示例:
+  # 解码 mask（0x 前缀可省略）
+  k8s-toolkit signals decode 0x0000000000004a02
+
+  # 以 YAML 输出
+  k8s-toolkit signals decode 0000000180014a07 -o yaml
+
+  # 将信号列表编码为 mask（SIG 前缀可省略，也可以使用信号编号）
+  k8s-toolkit signals encode SIGTERM,SIGINT
+  k8s-toolkit signals encode hup usr1 34
+
+  # 实时信号使用 SIGRTMIN+n / SIGRTMAX-n 命名 (与 kill -l 相同，SIGRTMIN 为 34)
+  k8s-toolkit signals encode SIGRTMIN+3
+
+  # 解码其他体系结构机器上复制来的 mask（mips、alpha、sparc 的信号编号不同）
+  k8s-toolkit signals decode 0x0000000000008000 --arch mips`,
+}
+
+var signalsDecodeCmd = &cobra.Command{
+	Use:   "decode MASK [MASK...]",
+	Short: "将信号 mask 解码为名称列表",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		table, err := procinfo.SignalTableFor(signalsArch)
+		if err != nil {
+			return err
+		}
+		return decodeMasks(args, signalsOutput, table.MaskDocument)
+	},
+}
+
+var signalsEncodeCmd = &cobra.Command{
+	Use:   "encode NAME[,NAME...] [NAME...]",
+	Short: "将信号名称列表编码为 mask",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		table, err := procinfo.SignalTableFor(signalsArch)
+		if err != nil {
+			return err
+		}
+		mask, err := table.Encode(splitNameArgs(args))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("0x%016x\n", mask)
+		return nil
+	},
+}
+
+var (
+	signalsOutput string
+	signalsArch   string
+)
+
+func init() {
+	rootCmd.AddCommand(signalsCmd)
+	signalsCmd.AddCommand(signalsDecodeCmd)
+	signalsCmd.AddCommand(signalsEncodeCmd)
+
+	signalsCmd.PersistentFlags().StringVar(&signalsArch, "arch", "",
+		"信号编号所属的体系结构: generic|mips|alpha|sparc，也接受 GOARCH 值 (默认: 本机)")
+	signalsCmd.RegisterFlagCompletionFunc("arch",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return procinfo.SignalArchs, cobra.ShellCompDirectiveNoFileComp
+		})
+
+	signalsDecodeCmd.Flags().StringVarP(&signalsOutput, "output", "o", "",
+		"输出格式: json 或 yaml (默认: 可读文本)")
+}
@@ -0,0 +1,265 @@
+package imgsync
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/registry"
+)
+
+// dockerHubAuthKey docker login 保存 Docker Hub 凭据时使用的键
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// errHelperNotFound config.json 中配置的 docker-credential-<helper> 不在 PATH 中
+var errHelperNotFound = errors.New("credential helper not found")
+
+// AuthConfig 一个镜像仓库的凭据
+// IdentityToken 不为空时使用 token 认证，Username/Password 被忽略
+type AuthConfig struct {
+	Username      string
+	Password      string
+	IdentityToken string
+}
+
+// Credentials 镜像仓库凭据的来源，Docker 拉取和 containerd 拉取使用同一份凭据
+// 查找顺序: 显式指定的用户名和密码 (只用于 explicitHost)、config.json 的 credHelpers、credsStore、auths
+type Credentials struct {
+	explicit     *AuthConfig   // --registry-user/--registry-password-stdin
+	explicitHost string        // explicit 适用的镜像仓库，其他仓库 (包括 mirror) 使用配置文件中的凭据
+	config       *dockerConfig // ~/.docker/config.json，不存在时为 nil
+}
+
+// dockerConfig docker CLI 的配置文件中与认证相关的部分
+type dockerConfig struct {
+	Auths       map[string]dockerAuthEntry `json:"auths"`
+	CredsStore  string                     `json:"credsStore"`
+	CredHelpers map[string]string          `json:"credHelpers"`
+}
+
+type dockerAuthEntry struct {
+	Auth          string `json:"auth"` // base64(username:password)
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+// LoadCredentials 读取 docker 配置文件 ($DOCKER_CONFIG/config.json 或 ~/.docker/config.json)
+// username 不为空时显式凭据只用于 host 指定的镜像仓库，并优先于配置文件
+func LoadCredentials(host, username, password string) (*Credentials, error) {
+	creds := &Credentials{}
+	if username != "" {
+		if host == "" {
+			return nil, fmt.Errorf("没有指定凭据适用的镜像仓库")
+		}
+		creds.explicit = &AuthConfig{Username: username, Password: password}
+		creds.explicitHost = host
+	}
+
+	path := dockerConfigPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return creds, nil
+		}
+		return nil, fmt.Errorf("读取 %s 失败: %w", path, err)
+	}
+
+	var cfg dockerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+	}
+	creds.config = &cfg
+	return creds, nil
+}
+
+// dockerConfigPath 返回 docker 配置文件的路径
+func dockerConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// Lookup 查找镜像仓库的凭据，没有找到时返回 nil
+// host 可以是 docker.io、registry-1.docker.io 或 harbor.example.com:8443 这样的仓库地址
+// 没有显式指定凭据时，无法运行的 credential helper (如从其他机器复制来的 config.json) 按没有凭据处理，
+// 以便匿名拉取公开镜像
+func (c *Credentials) Lookup(host string) (*AuthConfig, error) {
+	if c == nil {
+		return nil, nil
+	}
+	keys := authKeys(host)
+	if c.explicit != nil && slices.Contains(keys, authKeys(c.explicitHost)[0]) {
+		return c.explicit, nil
+	}
+	if c.config == nil {
+		return nil, nil
+	}
+
+	for _, key := range keys {
+		if helper, ok := c.config.CredHelpers[key]; ok && helper != "" {
+			auth, err := runCredentialHelper(helper, key)
+			if errors.Is(err, errHelperNotFound) && c.explicit == nil {
+				return nil, nil
+			}
+			return auth, err
+		}
+	}
+	if c.config.CredsStore != "" {
+		auth, err := runCredentialHelper(c.config.CredsStore, keys[0])
+		if errors.Is(err, errHelperNotFound) && c.explicit == nil {
+			err = nil
+		}
+		if err != nil || auth != nil {
+			return auth, err
+		}
+	}
+
+	for key, entry := range c.config.Auths {
+		if !matchAuthKey(key, keys) {
+			continue
+		}
+		auth, err := decodeAuthEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("解析 %s 的凭据失败: %w", key, err)
+		}
+		return auth, nil
+	}
+	return nil, nil
+}
+
+// ContainerdCredentials 返回 containerd resolver 使用的凭据回调
+// 使用 identity token 时用户名为空，containerd 会将 secret 作为 refresh token
+func (c *Credentials) ContainerdCredentials(host string) (string, string, error) {
+	auth, err := c.Lookup(host)
+	if err != nil || auth == nil {
+		return "", "", err
+	}
+	if auth.IdentityToken != "" {
+		return "", auth.IdentityToken, nil
+	}
+	return auth.Username, auth.Password, nil
+}
+
+// DockerRegistryAuth 返回 Docker API 拉取镜像时使用的 RegistryAuth (base64 编码的 JSON)
+// 没有找到凭据时返回空字符串，由 Docker daemon 匿名拉取
+func (c *Credentials) DockerRegistryAuth(imageName string) (string, error) {
+	host, err := RegistryHost(imageName)
+	if err != nil {
+		return "", err
+	}
+
+	auth, err := c.Lookup(host)
+	if err != nil || auth == nil {
+		return "", err
+	}
+	return registry.EncodeAuthConfig(registry.AuthConfig{
+		Username:      auth.Username,
+		Password:      auth.Password,
+		IdentityToken: auth.IdentityToken,
+		ServerAddress: host,
+	})
+}
+
+// RegistryHost 返回镜像所在的镜像仓库地址，没有仓库前缀的镜像属于 docker.io
+func RegistryHost(imageName string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return "", fmt.Errorf("无效的镜像名称 '%s': %w", imageName, err)
+	}
+	return reference.Domain(named), nil
+}
+
+// authKeys 返回在 config.json 中查找 host 时使用的键，第一个是首选键
+// Docker Hub 的凭据保存在 https://index.docker.io/v1/ 下
+func authKeys(host string) []string {
+	switch host {
+	case "docker.io", "index.docker.io", "registry-1.docker.io":
+		return []string{dockerHubAuthKey, "index.docker.io", "docker.io", "registry-1.docker.io"}
+	}
+	return []string{host}
+}
+
+// matchAuthKey 判断 auths 中的键是否对应 keys 中的仓库
+// 键可能带有 scheme 和路径，如 https://harbor.example.com/v2/
+func matchAuthKey(key string, keys []string) bool {
+	host := key
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexByte(host, '/'); i >= 0 {
+		host = host[:i]
+	}
+	for _, k := range keys {
+		if key == k || host == k {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeAuthEntry 解析 auths 中的一项，auth 字段优先于 username/password
+func decodeAuthEntry(entry dockerAuthEntry) (*AuthConfig, error) {
+	auth := &AuthConfig{
+		Username:      entry.Username,
+		Password:      entry.Password,
+		IdentityToken: entry.IdentityToken,
+	}
+	if entry.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			return nil, err
+		}
+		user, pass, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return nil, fmt.Errorf("auth 字段格式无效")
+		}
+		auth.Username, auth.Password = user, pass
+	}
+	return auth, nil
+}
+
+// runCredentialHelper 调用 docker-credential-<helper> get 获取凭据
+// helper 报告凭据不存在时返回 nil
+func runCredentialHelper(helper, serverURL string) (*AuthConfig, error) {
+	program := "docker-credential-" + helper
+	cmd := exec.Command(program, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", errHelperNotFound, program)
+		}
+		// helper 约定在找不到凭据时输出 "credentials not found in native keychain"
+		if strings.Contains(stdout.String()+stderr.String(), "credentials not found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s get 失败: %v %s", program, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var resp struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("解析 %s 的输出失败: %w", program, err)
+	}
+	// 用户名为 <token> 表示 Secret 是 identity token
+	if resp.Username == "<token>" {
+		return &AuthConfig{IdentityToken: resp.Secret}, nil
+	}
+	return &AuthConfig{Username: resp.Username, Password: resp.Secret}, nil
+}
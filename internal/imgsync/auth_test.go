@@ -0,0 +1,197 @@
+package imgsync
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAuthKeys(t *testing.T) {
+	hub := []string{dockerHubAuthKey, "index.docker.io", "docker.io", "registry-1.docker.io"}
+	tests := []struct {
+		host string
+		want []string
+	}{
+		{host: "docker.io", want: hub},
+		{host: "index.docker.io", want: hub},
+		{host: "registry-1.docker.io", want: hub},
+		{host: "harbor.example.com", want: []string{"harbor.example.com"}},
+		{host: "harbor.example.com:8443", want: []string{"harbor.example.com:8443"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := authKeys(tt.host); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("authKeys(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchAuthKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		host string
+		want bool
+	}{
+		{key: "https://index.docker.io/v1/", host: "docker.io", want: true},
+		{key: "https://index.docker.io/v1/", host: "registry-1.docker.io", want: true},
+		{key: "index.docker.io", host: "docker.io", want: true},
+		{key: "docker.io", host: "index.docker.io", want: true},
+		{key: "harbor.example.com", host: "harbor.example.com", want: true},
+		{key: "https://harbor.example.com/v2/", host: "harbor.example.com", want: true},
+		{key: "https://harbor.example.com:8443", host: "harbor.example.com:8443", want: true},
+		{key: "harbor.example.com:8443", host: "harbor.example.com", want: false},
+		{key: "harbor.example.com", host: "harbor.example.com:8443", want: false},
+		{key: "https://index.docker.io/v1/", host: "harbor.example.com", want: false},
+		{key: "harbor.example.com.evil.io", host: "harbor.example.com", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"/"+tt.host, func(t *testing.T) {
+			if got := matchAuthKey(tt.key, authKeys(tt.host)); got != tt.want {
+				t.Errorf("matchAuthKey(%q, %q) = %t, want %t", tt.key, tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+// writeHelper 在 dir 中创建一个 docker-credential-<name>，对 found 中的服务器返回用户名 user
+func writeHelper(t *testing.T, dir, name, user string, found ...string) {
+	t.Helper()
+	var cases strings.Builder
+	for _, server := range found {
+		cases.WriteString(server + ") echo '{\"Username\":\"" + user + "\",\"Secret\":\"s3cret\"}' ;;\n")
+	}
+	script := "#!/bin/sh\nread server\ncase \"$server\" in\n" + cases.String() +
+		"*) echo 'credentials not found in native keychain'; exit 1 ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(dir, "docker-credential-"+name), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setupDockerConfig 写入 config.json 并将 helper 所在目录加入 PATH
+func setupDockerConfig(t *testing.T, config string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+func TestCredentialsLookup(t *testing.T) {
+	hubAuth := base64.StdEncoding.EncodeToString([]byte("hubuser:hubpass"))
+	harborAuth := base64.StdEncoding.EncodeToString([]byte("robot:token"))
+	dir := setupDockerConfig(t, `{
+		"auths": {
+			"https://index.docker.io/v1/": {"auth": "`+hubAuth+`"},
+			"https://harbor.example.com:8443/v2/": {"auth": "`+harborAuth+`"},
+			"quay.io": {}
+		},
+		"credsStore": "store",
+		"credHelpers": {"gcr.io": "gcloud", "ghcr.io": "store"}
+	}`)
+	writeHelper(t, dir, "store", "from-store", "quay.io", "ghcr.io")
+	writeHelper(t, dir, "gcloud", "from-gcloud", "gcr.io")
+
+	creds, err := LoadCredentials("", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		host     string
+		wantUser string // 空表示没有凭据
+	}{
+		{host: "gcr.io", wantUser: "from-gcloud"},           // credHelpers 优先于 credsStore
+		{host: "ghcr.io", wantUser: "from-store"},           // credHelpers 指定的 helper
+		{host: "quay.io", wantUser: "from-store"},           // credsStore 优先于 auths
+		{host: "docker.io", wantUser: "hubuser"},            // credsStore 中没有，回退到 auths
+		{host: "registry-1.docker.io", wantUser: "hubuser"}, // Docker Hub 的其他地址
+		{host: "harbor.example.com:8443", wantUser: "robot"},
+		{host: "harbor.example.com"}, // 端口不同是不同的仓库
+		{host: "registry.k8s.io"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			auth, err := creds.Lookup(tt.host)
+			if err != nil {
+				t.Fatalf("Lookup(%q): %v", tt.host, err)
+			}
+			var user string
+			if auth != nil {
+				user = auth.Username
+			}
+			if user != tt.wantUser {
+				t.Errorf("Lookup(%q) user = %q, want %q", tt.host, user, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestCredentialsLookupExplicit(t *testing.T) {
+	setupDockerConfig(t, `{"auths": {"harbor.example.com": {"username": "config", "password": "x"}}}`)
+
+	creds, err := LoadCredentials("docker.io", "explicit", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for host, want := range map[string]string{
+		"docker.io":            "explicit",
+		"index.docker.io":      "explicit",
+		"registry-1.docker.io": "explicit",
+		"harbor.example.com":   "config",
+		"mirror.example.com":   "",
+	} {
+		auth, err := creds.Lookup(host)
+		if err != nil {
+			t.Fatalf("Lookup(%q): %v", host, err)
+		}
+		var user string
+		if auth != nil {
+			user = auth.Username
+		}
+		if user != want {
+			t.Errorf("Lookup(%q) user = %q, want %q", host, user, want)
+		}
+	}
+
+	if _, err := LoadCredentials("", "explicit", "pass"); err == nil {
+		t.Error("LoadCredentials without a registry host should fail")
+	}
+}
+
+func TestCredentialsLookupMissingHelper(t *testing.T) {
+	setupDockerConfig(t, `{
+		"credsStore": "does-not-exist",
+		"credHelpers": {"gcr.io": "does-not-exist"}
+	}`)
+
+	// 没有显式凭据时按匿名拉取处理
+	anonymous, err := LoadCredentials("", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{"docker.io", "gcr.io"} {
+		auth, err := anonymous.Lookup(host)
+		if err != nil || auth != nil {
+			t.Errorf("Lookup(%q) = %+v, %v; want anonymous", host, auth, err)
+		}
+	}
+
+	// 显式指定了凭据时不掩盖配置错误
+	explicit, err := LoadCredentials("harbor.example.com", "robot", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := explicit.Lookup("gcr.io"); err == nil {
+		t.Error("Lookup with a missing helper and explicit credentials should fail")
+	}
+}
@@ -0,0 +1,298 @@
+package imgsync
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"sigs.k8s.io/yaml"
+)
+
+// BatchImage 批量同步中的一个镜像
+type BatchImage struct {
+	Name      string   `json:"image"`
+	Platforms []string `json:"platforms,omitempty"` // 为空时拉取本机平台
+	Nodes     []string `json:"nodes,omitempty"`     // 节点或节点组名称，为空时使用 -n 指定的节点
+}
+
+// BatchManifest 批量同步的镜像清单
+//
+// YAML 格式:
+//
+//	nodeGroups:
+//	  gpu: [gpu-1, gpu-2]
+//	  edge: [edge-1:2222]
+//	images:
+//	  - image: nginx:1.27
+//	    platforms: [linux/amd64, linux/arm64]
+//	    nodes: [gpu, edge]
+//	  - image: redis:7
+type BatchManifest struct {
+	NodeGroups map[string][]string `json:"nodeGroups,omitempty"`
+	Images     []BatchImage        `json:"images"`
+}
+
+// LoadBatchFile 读取镜像清单，.yaml/.yml 文件按 YAML 清单解析，
+// 其他文件每行一个镜像，忽略空行和 # 开头的注释
+func LoadBatchFile(path string) (*BatchManifest, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 失败: %w", path, err)
+		}
+		var manifest BatchManifest
+		if err := yaml.UnmarshalStrict(data, &manifest); err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+		}
+		return &manifest, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %w", path, err)
+	}
+	defer f.Close()
+
+	manifest := &BatchManifest{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			manifest.Images = append(manifest.Images, BatchImage{Name: line})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %w", path, err)
+	}
+	return manifest, nil
+}
+
+// Resolve 将节点组展开为节点列表，没有指定节点的镜像使用 defaultNodes
+// 不是节点组名称的项按节点地址处理；同一镜像的节点去重
+func (m *BatchManifest) Resolve(defaultNodes []string) ([]BatchImage, error) {
+	if len(m.Images) == 0 {
+		return nil, fmt.Errorf("镜像清单中没有镜像")
+	}
+
+	resolved := make([]BatchImage, 0, len(m.Images))
+	for i, img := range m.Images {
+		if img.Name == "" {
+			return nil, fmt.Errorf("第 %d 个镜像缺少 image 字段", i+1)
+		}
+		targets := img.Nodes
+		if len(targets) == 0 {
+			targets = defaultNodes
+		}
+
+		var nodes []string
+		seen := make(map[string]bool)
+		for _, target := range targets {
+			members, ok := m.NodeGroups[target]
+			if !ok {
+				members = []string{target}
+			}
+			for _, node := range members {
+				if !seen[node] {
+					seen[node] = true
+					nodes = append(nodes, node)
+				}
+			}
+		}
+		resolved = append(resolved, BatchImage{Name: img.Name, Platforms: img.Platforms, Nodes: nodes})
+	}
+	return resolved, nil
+}
+
+// BatchOptions 批量同步选项
+type BatchOptions struct {
+	Source      string       // 镜像来源: docker (默认) 或 registry
+	Credentials *Credentials // 镜像仓库凭据，nil 表示匿名拉取
+	Parallel    int          // 同时拉取的镜像数，小于 1 时为 1
+	Verbose     bool
+	ProgressCb  func(stage string, progress float64, message string)
+}
+
+// BatchResult 批量同步结果
+type BatchResult struct {
+	Images   []BatchImageResult // 与输入的镜像顺序相同
+	Nodes    []string           // 所有目标节点，按首次出现的顺序
+	Duration time.Duration
+}
+
+// BatchImageResult 一个镜像的同步结果
+type BatchImageResult struct {
+	ImageName     string
+	Digest        string
+	LocalUpToDate bool
+	Err           error                 // 拉取或本地导入失败，此时不会分发
+	Nodes         map[string]NodeResult // 节点 -> 分发结果，只包含该镜像的目标节点
+}
+
+// batchJob 分发到一个节点的镜像
+type batchJob struct {
+	index int
+	local *localImage
+}
+
+// SyncImages 批量同步镜像
+// 拉取以 Parallel 为上限并发执行，每个镜像拉取完成后立即进入其目标节点的分发队列；
+// 每个节点只建立一个 SSH 连接，队列中的镜像按完成顺序逐个分发，后面的镜像可以复用前面导入的 layer
+func SyncImages(ctx context.Context, imgs []BatchImage, opts BatchOptions) (*BatchResult, error) {
+	startTime := time.Now()
+
+	progress := func(stage string, pct float64, msg string) {
+		if opts.ProgressCb != nil {
+			opts.ProgressCb(stage, pct, msg)
+		}
+	}
+
+	result := &BatchResult{Images: make([]BatchImageResult, len(imgs))}
+	for i, img := range imgs {
+		result.Images[i] = BatchImageResult{ImageName: img.Name, Nodes: make(map[string]NodeResult)}
+		for _, node := range img.Nodes {
+			if !slices.Contains(result.Nodes, node) {
+				result.Nodes = append(result.Nodes, node)
+			}
+		}
+	}
+
+	var sshConfig *ssh.ClientConfig
+	if len(result.Nodes) > 0 {
+		var err error
+		sshConfig, err = getDefaultSSHConfig()
+		if err != nil {
+			return nil, fmt.Errorf("获取 SSH 配置失败: %w", err)
+		}
+	}
+
+	clients, err := newSyncClients(opts.Source, opts.Credentials, progress)
+	if err != nil {
+		return nil, err
+	}
+	defer clients.Close()
+
+	var mu sync.Mutex
+	record := func(index int, node string, r NodeResult) {
+		mu.Lock()
+		result.Images[index].Nodes[node] = r
+		mu.Unlock()
+	}
+
+	// 每个节点一个分发队列
+	queues := make(map[string]chan batchJob, len(result.Nodes))
+	var nodeWg sync.WaitGroup
+	for _, node := range result.Nodes {
+		queue := make(chan batchJob, len(imgs))
+		queues[node] = queue
+		nodeWg.Add(1)
+		go func(node string) {
+			defer nodeWg.Done()
+			runNodeQueue(ctx, node, queue, clients.ctrd, sshConfig, imgs, opts, record, progress)
+		}(node)
+	}
+
+	// 有上限的并发拉取
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+	sem := make(chan struct{}, parallel)
+	var pullWg sync.WaitGroup
+	var done int
+	for i, img := range imgs {
+		pullWg.Add(1)
+		go func(index int, img BatchImage) {
+			defer pullWg.Done()
+			sem <- struct{}{}
+			imageProgress := func(stage string, pct float64, msg string) {
+				progress(stage, pct, fmt.Sprintf("%s: %s", img.Name, msg))
+			}
+			local, err := clients.pull(ctx, img.Name, img.Platforms, opts.Verbose, imageProgress)
+			<-sem
+
+			mu.Lock()
+			done++
+			finished := done
+			r := &result.Images[index]
+			if err != nil {
+				r.Err = err
+			} else {
+				r.Digest, r.LocalUpToDate = local.digest, local.upToDate
+			}
+			mu.Unlock()
+
+			if err != nil {
+				progress("拉取", float64(finished)/float64(len(imgs)), fmt.Sprintf("%s: %v", img.Name, err))
+				return
+			}
+			progress("拉取", float64(finished)/float64(len(imgs)), fmt.Sprintf("%s: 本地就绪 (%d/%d)", img.Name, finished, len(imgs)))
+			for _, node := range img.Nodes {
+				queues[node] <- batchJob{index: index, local: local}
+			}
+		}(i, img)
+	}
+
+	pullWg.Wait()
+	for _, queue := range queues {
+		close(queue)
+	}
+	nodeWg.Wait()
+
+	result.Duration = time.Since(startTime)
+	progress("完成", 1.0, fmt.Sprintf("总耗时: %v", result.Duration))
+	return result, nil
+}
+
+// runNodeQueue 逐个分发队列中的镜像到节点，第一个镜像到达时建立 SSH 连接
+// 连接失败时队列中的所有镜像都记录同一个错误
+func runNodeQueue(ctx context.Context, node string, queue <-chan batchJob, ctrd *ContainerdClient, sshConfig *ssh.ClientConfig,
+	imgs []BatchImage, opts BatchOptions, record func(int, string, NodeResult), progress progressFunc) {
+	var conn *nodeConn
+	var dialErr error
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+
+	for job := range queue {
+		if conn == nil && dialErr == nil {
+			conn, dialErr = dialNode(node, sshConfig, opts.Verbose)
+		}
+		if dialErr != nil {
+			record(job.index, node, NodeResult{Err: dialErr})
+			continue
+		}
+
+		imageSize, _ := ctrd.GetImageSize(ctx, job.local.name)
+		r := distributeImage(ctx, conn, ctrd, job.local.name, DistributeOptions{
+			Verbose:   opts.Verbose,
+			ImageSize: imageSize,
+			Digest:    job.local.digest,
+			ProgressCb: func(node string, written, total int64, pct float64) {
+				if opts.Verbose {
+					progress("分发", 0.8, fmt.Sprintf("%s -> %s: 进度 %.1f%% (%s / %s)",
+						imgs[job.index].Name, node, pct, formatBytes(written), formatBytes(total)))
+				}
+			},
+		})
+		record(job.index, node, r)
+
+		switch {
+		case r.Err != nil:
+			progress("分发", 0.8, fmt.Sprintf("%s -> %s: %v", imgs[job.index].Name, node, r.Err))
+		case r.UpToDate:
+			progress("分发", 0.8, fmt.Sprintf("%s -> %s: 已是最新", imgs[job.index].Name, node))
+		default:
+			progress("分发", 0.8, fmt.Sprintf("%s -> %s: 同步成功 (传输 %s)", imgs[job.index].Name, node, formatBytes(r.Transferred)))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package imgsync
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeBatchFile 在临时目录中写入镜像清单
+func writeBatchFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadBatchFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    *BatchManifest
+		wantErr bool
+	}{
+		{
+			name: "plain list with comments",
+			file: "images.txt",
+			content: `# 基础镜像
+nginx:1.27
+  redis:7   # 缓存
+
+harbor.example.com/app/api:v1#no-space-comment
+	# 缩进的注释
+`,
+			want: &BatchManifest{Images: []BatchImage{
+				{Name: "nginx:1.27"},
+				{Name: "redis:7"},
+				{Name: "harbor.example.com/app/api:v1"},
+			}},
+		},
+		{
+			name:    "empty list",
+			file:    "images.list",
+			content: "# nothing yet\n\n",
+			want:    &BatchManifest{},
+		},
+		{
+			name: "yaml manifest",
+			file: "release.yaml",
+			content: `nodeGroups:
+  gpu: [gpu-1, gpu-2]
+images:
+  - image: nginx:1.27
+    platforms: [linux/amd64, linux/arm64]
+    nodes: [gpu, edge-1]
+  - image: redis:7
+`,
+			want: &BatchManifest{
+				NodeGroups: map[string][]string{"gpu": {"gpu-1", "gpu-2"}},
+				Images: []BatchImage{
+					{Name: "nginx:1.27", Platforms: []string{"linux/amd64", "linux/arm64"}, Nodes: []string{"gpu", "edge-1"}},
+					{Name: "redis:7"},
+				},
+			},
+		},
+		{
+			name:    "yml extension is case insensitive",
+			file:    "release.YML",
+			content: "images:\n  - image: busybox\n",
+			want:    &BatchManifest{Images: []BatchImage{{Name: "busybox"}}},
+		},
+		{
+			name: "yaml rejects unknown fields",
+			file: "release.yaml",
+			content: `images:
+  - image: nginx:1.27
+    platform: linux/arm64
+`,
+			wantErr: true,
+		},
+		{
+			name:    "yaml rejects unknown top-level fields",
+			file:    "release.yaml",
+			content: "groups: {}\nimages: []\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadBatchFile(writeBatchFile(t, tt.file, tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadBatchFile error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadBatchFile = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := LoadBatchFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadBatchFile of a missing file should fail")
+	}
+}
+
+func TestBatchManifestResolve(t *testing.T) {
+	groups := map[string][]string{
+		"gpu":  {"gpu-1", "gpu-2"},
+		"edge": {"edge-1:2222", "gpu-2"},
+	}
+
+	tests := []struct {
+		name         string
+		manifest     BatchManifest
+		defaultNodes []string
+		want         []BatchImage
+		wantErr      bool
+	}{
+		{
+			name:         "falls back to default nodes",
+			manifest:     BatchManifest{Images: []BatchImage{{Name: "nginx"}, {Name: "redis", Nodes: []string{"node3"}}}},
+			defaultNodes: []string{"node1", "node2"},
+			want: []BatchImage{
+				{Name: "nginx", Nodes: []string{"node1", "node2"}},
+				{Name: "redis", Nodes: []string{"node3"}},
+			},
+		},
+		{
+			name:     "no nodes at all",
+			manifest: BatchManifest{Images: []BatchImage{{Name: "nginx"}}},
+			want:     []BatchImage{{Name: "nginx"}},
+		},
+		{
+			name: "expands groups and dedups nodes",
+			manifest: BatchManifest{
+				NodeGroups: groups,
+				Images: []BatchImage{{
+					Name:      "nginx",
+					Platforms: []string{"linux/arm64"},
+					Nodes:     []string{"gpu", "edge", "gpu-1", "node9"},
+				}},
+			},
+			want: []BatchImage{
+				{Name: "nginx", Platforms: []string{"linux/arm64"}, Nodes: []string{"gpu-1", "gpu-2", "edge-1:2222", "node9"}},
+			},
+		},
+		{
+			name: "default nodes may name groups",
+			manifest: BatchManifest{
+				NodeGroups: groups,
+				Images:     []BatchImage{{Name: "nginx"}},
+			},
+			defaultNodes: []string{"gpu", "node1"},
+			want:         []BatchImage{{Name: "nginx", Nodes: []string{"gpu-1", "gpu-2", "node1"}}},
+		},
+		{
+			name:     "empty manifest",
+			manifest: BatchManifest{},
+			wantErr:  true,
+		},
+		{
+			name:     "image without a name",
+			manifest: BatchManifest{Images: []BatchImage{{Name: "nginx"}, {Nodes: []string{"node1"}}}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.manifest.Resolve(tt.defaultNodes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resolve = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,14 +6,20 @@ import (
 	"io"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
 	"github.com/containerd/containerd/v2/core/images/archive"
 	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/platforms"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // ContainerdClient 封装 Containerd 客户端操作
 type ContainerdClient struct {
 	client    *containerd.Client
 	namespace string
+	creds     *Credentials // Pull 使用的镜像仓库凭据
 }
 
 // ContainerdOptions 创建客户端的选项
@@ -55,6 +61,11 @@ func (c *ContainerdClient) Close() error {
 	return c.client.Close()
 }
 
+// SetCredentials 设置 Pull 使用的镜像仓库凭据，nil 表示匿名拉取
+func (c *ContainerdClient) SetCredentials(creds *Credentials) {
+	c.creds = creds
+}
+
 // ImportFromStream 从 tar 流导入镜像到 containerd
 func (c *ContainerdClient) ImportFromStream(ctx context.Context, reader io.Reader) ([]string, error) {
 	// 设置 namespace 上下文
@@ -75,6 +86,64 @@ func (c *ContainerdClient) ImportFromStream(ctx context.Context, reader io.Reade
 	return imported, nil
 }
 
+// Pull 通过 containerd 的 resolver 直接从镜像仓库拉取镜像，不需要 Docker
+// platformList 为空时拉取本机平台，否则逐个拉取指定的平台 (如 linux/arm64)，只解包与本机匹配的平台
+// 每个开始下载的 manifest、config 和 layer 都会回调一次 progressCb，返回镜像在 containerd 中的完整名称
+func (c *ContainerdClient) Pull(ctx context.Context, imageName string, platformList []string, progressCb func(PullProgress)) (string, error) {
+	ctx = namespaces.WithNamespace(ctx, c.namespace)
+
+	ref, err := normalizeImageRef(imageName)
+	if err != nil {
+		return "", err
+	}
+
+	opts := []containerd.RemoteOpt{
+		containerd.WithResolver(newRegistryResolver(ctx, c.creds)),
+	}
+	if progressCb != nil {
+		opts = append(opts, containerd.WithImageHandler(images.HandlerFunc(
+			func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+				var p PullProgress
+				p.ID = desc.Digest.Encoded()
+				if len(p.ID) > 12 {
+					p.ID = p.ID[:12]
+				}
+				p.Status = fmt.Sprintf("%s: Fetching %s", p.ID, desc.MediaType)
+				p.Progress = formatBytes(desc.Size)
+				p.ProgressDetail.Total = desc.Size
+				progressCb(p)
+				return nil, nil
+			})))
+	}
+
+	if len(platformList) == 0 {
+		img, err := c.client.Pull(ctx, ref, append(opts, containerd.WithPullUnpack)...)
+		if err != nil {
+			return "", fmt.Errorf("从镜像仓库拉取 %s 失败: %w", ref, err)
+		}
+		return img.Name(), nil
+	}
+
+	// 每次 Pull 只会选择一个平台的 manifest，多个平台逐个拉取，镜像记录指向同一个 index
+	var name string
+	for _, p := range platformList {
+		spec, err := platforms.Parse(p)
+		if err != nil {
+			return "", fmt.Errorf("无效的平台 '%s': %w", p, err)
+		}
+		popts := append(opts[:len(opts):len(opts)], containerd.WithPlatform(platforms.Format(spec)))
+		if platforms.Default().Match(spec) {
+			popts = append(popts, containerd.WithPullUnpack)
+		}
+		img, err := c.client.Pull(ctx, ref, popts...)
+		if err != nil {
+			return "", fmt.Errorf("从镜像仓库拉取 %s (%s) 失败: %w", ref, platforms.Format(spec), err)
+		}
+		name = img.Name()
+	}
+	return name, nil
+}
+
 // ListImages 列出所有镜像
 func (c *ContainerdClient) ListImages(ctx context.Context) ([]string, error) {
 	ctx = namespaces.WithNamespace(ctx, c.namespace)
@@ -97,13 +166,63 @@ func (c *ContainerdClient) ImageExists(ctx context.Context, imageName string) (b
 
 	_, err := c.client.GetImage(ctx, imageName)
 	if err != nil {
-		// containerd 没有明确的 NotFound 错误类型，检查错误消息
-		return false, nil
+		if errdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("获取镜像失败: %w", err)
 	}
 	return true, nil
 }
 
+// ImageDigest 返回镜像的 target digest (index 或 manifest)，与 ctr images ls 的 DIGEST 列相同
+// 镜像不存在时返回空字符串
+func (c *ContainerdClient) ImageDigest(ctx context.Context, imageName string) (string, error) {
+	ctx = namespaces.WithNamespace(ctx, c.namespace)
+
+	img, err := c.client.ImageService().Get(ctx, imageName)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("获取镜像失败: %w", err)
+	}
+	return img.Target.Digest.String(), nil
+}
+
+// ImageLabel 返回镜像的标签值，镜像或标签不存在时返回空字符串
+func (c *ContainerdClient) ImageLabel(ctx context.Context, imageName, key string) (string, error) {
+	ctx = namespaces.WithNamespace(ctx, c.namespace)
+
+	img, err := c.client.ImageService().Get(ctx, imageName)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("获取镜像失败: %w", err)
+	}
+	return img.Labels[key], nil
+}
+
+// SetImageLabel 设置镜像的标签
+func (c *ContainerdClient) SetImageLabel(ctx context.Context, imageName, key, value string) error {
+	ctx = namespaces.WithNamespace(ctx, c.namespace)
+
+	img, err := c.client.ImageService().Get(ctx, imageName)
+	if err != nil {
+		return fmt.Errorf("获取镜像失败: %w", err)
+	}
+	if img.Labels == nil {
+		img.Labels = make(map[string]string)
+	}
+	img.Labels[key] = value
+	if _, err := c.client.ImageService().Update(ctx, img, "labels."+key); err != nil {
+		return fmt.Errorf("更新镜像标签失败: %w", err)
+	}
+	return nil
+}
+
 // ExportToStream 将镜像导出为 tar 流
+// 只导出本机平台的内容，Pull 只下载了本机平台的 layer，其他平台缺失的内容会被跳过
 func (c *ContainerdClient) ExportToStream(ctx context.Context, imageName string) (io.ReadCloser, error) {
 	ctx = namespaces.WithNamespace(ctx, c.namespace)
 
@@ -115,9 +234,87 @@ func (c *ContainerdClient) ExportToStream(ctx context.Context, imageName string)
 	pr, pw := io.Pipe()
 
 	go func() {
-		err := c.client.Export(ctx, pw, archive.WithImage(c.client.ImageService(), img.Name()))
+		err := c.client.Export(ctx, pw,
+			archive.WithImage(c.client.ImageService(), img.Name()),
+			archive.WithPlatform(platforms.Default()),
+			archive.WithSkipMissing(c.client.ContentStore()))
 		pw.CloseWithError(err)
 	}()
 
 	return pr, nil
 }
+
+// SaveToStream 与 ExportToStream 相同，使 ContainerdClient 可以作为 ImageSource 分发镜像
+func (c *ContainerdClient) SaveToStream(ctx context.Context, imageName string) (io.ReadCloser, error) {
+	return c.ExportToStream(ctx, imageName)
+}
+
+// GetImageSize 获取镜像在本机平台上的大小（压缩后的 layer、config 和 manifest，字节）
+func (c *ContainerdClient) GetImageSize(ctx context.Context, imageName string) (int64, error) {
+	ctx = namespaces.WithNamespace(ctx, c.namespace)
+
+	img, err := c.client.GetImage(ctx, imageName)
+	if err != nil {
+		return 0, fmt.Errorf("获取镜像信息失败: %w", err)
+	}
+	return img.Size(ctx)
+}
+
+// ImageBlobs 遍历镜像引用的全部 blob (index、manifest、config 和 layer)
+// 多平台镜像中本机没有拉取的平台被跳过；platform 不为 nil 时只包含与之匹配的平台
+// 镜像是 index 而没有任何匹配的平台时返回 ErrPlatformMissing
+func (c *ContainerdClient) ImageBlobs(ctx context.Context, imageName string, platform platforms.Matcher) (*ImageBlobs, error) {
+	ctx = namespaces.WithNamespace(ctx, c.namespace)
+
+	img, err := c.client.ImageService().Get(ctx, imageName)
+	if err != nil {
+		return nil, fmt.Errorf("获取镜像失败: %w", err)
+	}
+
+	cs := c.client.ContentStore()
+	result := &ImageBlobs{Ref: img.Name, Target: img.Target}
+	seen := make(map[string]bool)
+	manifests := 0
+	handler := images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		if seen[desc.Digest.String()] {
+			return nil, nil
+		}
+		if platform != nil && desc.Platform != nil && !platform.Match(*desc.Platform) {
+			return nil, images.ErrSkipDesc
+		}
+		if _, err := cs.Info(ctx, desc.Digest); err != nil {
+			isManifest := images.IsManifestType(desc.MediaType) || images.IsIndexType(desc.MediaType)
+			if errdefs.IsNotFound(err) && isManifest && desc.Digest != img.Target.Digest {
+				return nil, images.ErrSkipDesc
+			}
+			return nil, fmt.Errorf("读取 blob %s 失败: %w", desc.Digest, err)
+		}
+		seen[desc.Digest.String()] = true
+		if images.IsManifestType(desc.MediaType) {
+			manifests++
+		}
+		result.Blobs = append(result.Blobs, desc)
+		return images.Children(ctx, cs, desc)
+	})
+	if err := images.Walk(ctx, handler, img.Target); err != nil {
+		return nil, fmt.Errorf("遍历镜像内容失败: %w", err)
+	}
+	if manifests == 0 {
+		return nil, ErrPlatformMissing
+	}
+	return result, nil
+}
+
+// ReadBlob 从内容存储中读取一个 blob
+func (c *ContainerdClient) ReadBlob(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	ctx = namespaces.WithNamespace(ctx, c.namespace)
+
+	ra, err := c.client.ContentStore().ReaderAt(ctx, desc)
+	if err != nil {
+		return nil, fmt.Errorf("读取 blob %s 失败: %w", desc.Digest, err)
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{content.NewReader(ra), ra}, nil
+}
@@ -13,7 +13,8 @@ import (
 
 // DockerClient 封装 Docker 客户端操作
 type DockerClient struct {
-	cli *client.Client
+	cli   *client.Client
+	creds *Credentials // 拉取时使用的镜像仓库凭据
 }
 
 // NewDockerClient 创建 Docker 客户端
@@ -30,6 +31,25 @@ func (d *DockerClient) Close() error {
 	return d.cli.Close()
 }
 
+// SetCredentials 设置拉取镜像时使用的镜像仓库凭据，nil 表示只使用 Docker daemon 自身的配置
+func (d *DockerClient) SetCredentials(creds *Credentials) {
+	d.creds = creds
+}
+
+// pullOptions 返回带有镜像仓库凭据的拉取选项
+func (d *DockerClient) pullOptions(imageName string) (image.PullOptions, error) {
+	var opts image.PullOptions
+	if d.creds == nil {
+		return opts, nil
+	}
+	auth, err := d.creds.DockerRegistryAuth(imageName)
+	if err != nil {
+		return opts, fmt.Errorf("获取镜像仓库凭据失败: %w", err)
+	}
+	opts.RegistryAuth = auth
+	return opts, nil
+}
+
 // PullProgress 表示拉取进度信息
 type PullProgress struct {
 	Status         string `json:"status"`
@@ -42,9 +62,18 @@ type PullProgress struct {
 }
 
 // Pull 拉取镜像，返回进度信息
-func (d *DockerClient) Pull(ctx context.Context, imageName string, progressCb func(PullProgress)) error {
-	out, err := d.cli.ImagePull(ctx, imageName, image.PullOptions{})
+// platform 为空时由 Docker daemon 选择本机平台，否则拉取指定平台 (如 linux/arm64)
+func (d *DockerClient) Pull(ctx context.Context, imageName, platform string, progressCb func(PullProgress)) error {
+	pullOpts, err := d.pullOptions(imageName)
 	if err != nil {
+		return err
+	}
+	pullOpts.Platform = platform
+	out, err := d.cli.ImagePull(ctx, imageName, pullOpts)
+	if err != nil {
+		if platform != "" {
+			return fmt.Errorf("拉取 %s 平台镜像失败: %w", platform, err)
+		}
 		return fmt.Errorf("拉取镜像失败: %w", err)
 	}
 	defer out.Close()
@@ -88,6 +117,15 @@ func (d *DockerClient) ImageExists(ctx context.Context, imageName string) (bool,
 	return true, nil
 }
 
+// ImageID 获取镜像 ID (config 的 digest)
+func (d *DockerClient) ImageID(ctx context.Context, imageName string) (string, error) {
+	inspect, _, err := d.cli.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		return "", fmt.Errorf("获取镜像信息失败: %w", err)
+	}
+	return inspect.ID, nil
+}
+
 // GetImageSize 获取镜像大小（字节）
 func (d *DockerClient) GetImageSize(ctx context.Context, imageName string) (int64, error) {
 	inspect, _, err := d.cli.ImageInspectWithRaw(ctx, imageName)
@@ -99,19 +137,7 @@ func (d *DockerClient) GetImageSize(ctx context.Context, imageName string) (int6
 
 // PullPlatform 拉取指定平台的镜像
 func (d *DockerClient) PullPlatform(ctx context.Context, imageName, arch string) error {
-	platform := fmt.Sprintf("linux/%s", arch)
-
-	out, err := d.cli.ImagePull(ctx, imageName, image.PullOptions{
-		Platform: platform,
-	})
-	if err != nil {
-		return fmt.Errorf("拉取 %s 平台镜像失败: %w", platform, err)
-	}
-	defer out.Close()
-
-	// 消费输出流（避免阻塞）
-	_, err = io.Copy(io.Discard, out)
-	return err
+	return d.Pull(ctx, imageName, fmt.Sprintf("linux/%s", arch), nil)
 }
 
 // Tag 为镜像创建新标签
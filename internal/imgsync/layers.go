@@ -0,0 +1,181 @@
+package imgsync
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/platforms"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ErrPlatformMissing 本地 containerd 中没有与节点平台匹配的镜像内容
+var ErrPlatformMissing = errors.New("本地 containerd 中没有与节点平台匹配的镜像内容")
+
+// ImageBlobs 镜像在本地 containerd 内容存储中的 blob
+type ImageBlobs struct {
+	Ref    string               // 镜像在 containerd 中的完整名称
+	Target ocispec.Descriptor   // 镜像的 index 或 manifest
+	Blobs  []ocispec.Descriptor // 按遍历顺序排列，不重复
+}
+
+// BlobSource 可以按 blob 读取镜像内容的来源，ContainerdClient 实现了该接口
+// 分发时来源实现了该接口则只传输远程节点缺少的 layer，否则传输完整的镜像 tar 流
+type BlobSource interface {
+	ImageBlobs(ctx context.Context, imageName string, platform platforms.Matcher) (*ImageBlobs, error)
+	ReadBlob(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error)
+}
+
+// layerStream 只包含远程节点缺少的 blob 的镜像流
+type layerStream struct {
+	io.ReadCloser
+	Size   int64                // 流中 blob 的总大小，用于计算进度百分比
+	Reused int64                // 远程节点已有而没有传输的 layer 大小
+	Sent   []ocispec.Descriptor // 流中的 blob
+}
+
+// openLayerStream 查询远程节点内容存储中已有的 blob，返回一个 OCI layout tar 流
+// 流中包含节点平台缺少的 layer 以及全部 index、manifest 和 config，
+// 由 ctr images import 导入后使用远程节点已有的 layer 创建镜像记录
+func openLayerStream(ctx context.Context, conn *nodeConn, source BlobSource, imageName string) (*layerStream, error) {
+	image, err := source.ImageBlobs(ctx, imageName, conn.platform)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := conn.contentDigests()
+	if err != nil {
+		return nil, fmt.Errorf("查询远程节点的内容存储失败: %w", err)
+	}
+
+	stream := missingBlobs(image, remote)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeBlobArchive(ctx, pw, source, image, stream.Sent))
+	}()
+	stream.ReadCloser = pr
+	return stream, nil
+}
+
+// missingBlobs 按远程节点内容存储中已有的 blob 选出需要传输的 blob
+// manifest 和 config 很小，总是传输，保证导入时可以读取
+func missingBlobs(image *ImageBlobs, remote map[string]bool) *layerStream {
+	stream := &layerStream{}
+	for _, desc := range image.Blobs {
+		if images.IsLayerType(desc.MediaType) && remote[desc.Digest.String()] {
+			stream.Reused += desc.Size
+			continue
+		}
+		stream.Sent = append(stream.Sent, desc)
+		stream.Size += desc.Size
+	}
+	return stream
+}
+
+// contentDigests 通过 ctr content ls 查询节点 k8s.io namespace 中已有的 blob
+// 每个连接只查询一次，之后由 addContent 记录导入的 blob
+func (c *nodeConn) contentDigests() (map[string]bool, error) {
+	if c.content != nil {
+		return c.content, nil
+	}
+	out, err := c.output("ctr -n k8s.io content ls -q")
+	if err != nil {
+		return nil, err
+	}
+	c.content = make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			c.content[line] = true
+		}
+	}
+	return c.content, nil
+}
+
+// addContent 记录已导入节点的 blob
+func (c *nodeConn) addContent(blobs []ocispec.Descriptor) {
+	if c.content == nil {
+		return
+	}
+	for _, desc := range blobs {
+		c.content[desc.Digest.String()] = true
+	}
+}
+
+// writeBlobArchive 将 blobs 写为 OCI image layout 格式的 tar 流
+// index.json 指向镜像的 target，并以 io.containerd.image.name 注解记录镜像名称
+func writeBlobArchive(ctx context.Context, w io.Writer, source BlobSource, image *ImageBlobs, blobs []ocispec.Descriptor) error {
+	tw := tar.NewWriter(w)
+
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, ocispec.ImageLayoutFile, layout); err != nil {
+		return err
+	}
+
+	for _, desc := range blobs {
+		if err := writeTarBlob(ctx, tw, source, desc); err != nil {
+			return err
+		}
+	}
+
+	target := image.Target
+	target.Annotations = map[string]string{images.AnnotationImageName: image.Ref}
+	index := ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{target},
+	}
+	index.SchemaVersion = 2
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, ocispec.ImageIndexFile, data); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// writeTarBlob 将一个 blob 写入 blobs/<algorithm>/<encoded>
+func writeTarBlob(ctx context.Context, tw *tar.Writer, source BlobSource, desc ocispec.Descriptor) error {
+	reader, err := source.ReadBlob(ctx, desc)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	hdr := &tar.Header{
+		Name:     path.Join(ocispec.ImageBlobsDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded()),
+		Mode:     0444,
+		Size:     desc.Size,
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(tw, reader, desc.Size); err != nil {
+		return fmt.Errorf("写入 blob %s 失败: %w", desc.Digest, err)
+	}
+	return nil
+}
+
+// writeTarFile 将一个小文件写入 tar 流
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:     name,
+		Mode:     0444,
+		Size:     int64(len(data)),
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
@@ -0,0 +1,172 @@
+package imgsync
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/platforms"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// memBlobSource 内存中的内容存储，记录读取过的 blob
+type memBlobSource struct {
+	blobs map[digest.Digest][]byte
+	reads []digest.Digest
+}
+
+func (s *memBlobSource) add(mediaType string, data []byte) ocispec.Descriptor {
+	desc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(data), Size: int64(len(data))}
+	s.blobs[desc.Digest] = data
+	return desc
+}
+
+func (s *memBlobSource) ImageBlobs(context.Context, string, platforms.Matcher) (*ImageBlobs, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *memBlobSource) ReadBlob(_ context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	data, ok := s.blobs[desc.Digest]
+	if !ok {
+		return nil, errors.New("blob not found: " + desc.Digest.String())
+	}
+	s.reads = append(s.reads, desc.Digest)
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// testImage 构造一个单平台镜像: manifest、config 和三个 layer
+func testImage(t *testing.T, source *memBlobSource) *ImageBlobs {
+	t.Helper()
+	config := source.add(ocispec.MediaTypeImageConfig, []byte(`{"architecture":"amd64","os":"linux"}`))
+	var layers []ocispec.Descriptor
+	for _, data := range []string{"base layer", "app layer", "top layer"} {
+		layers = append(layers, source.add(ocispec.MediaTypeImageLayerGzip, []byte(data)))
+	}
+	manifest := ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: config, Layers: layers}
+	manifest.SchemaVersion = 2
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := source.add(ocispec.MediaTypeImageManifest, data)
+	return &ImageBlobs{
+		Ref:    "docker.io/library/app:v1",
+		Target: target,
+		Blobs:  append([]ocispec.Descriptor{target, config}, layers...),
+	}
+}
+
+// readArchive 按顺序返回 tar 流中的文件名和内容
+func readArchive(t *testing.T, r io.Reader) ([]string, map[string][]byte) {
+	t.Helper()
+	var names []string
+	files := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names, files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+		files[hdr.Name] = data
+	}
+}
+
+func blobPath(desc ocispec.Descriptor) string {
+	return path.Join(ocispec.ImageBlobsDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+}
+
+func TestWriteBlobArchive(t *testing.T) {
+	source := &memBlobSource{blobs: make(map[digest.Digest][]byte)}
+	image := testImage(t, source)
+	target, config, base, app, top := image.Blobs[0], image.Blobs[1], image.Blobs[2], image.Blobs[3], image.Blobs[4]
+
+	// 远程节点已有 base、top 两个 layer 和 config
+	remote := map[string]bool{
+		base.Digest.String():   true,
+		top.Digest.String():    true,
+		config.Digest.String(): true,
+	}
+	stream := missingBlobs(image, remote)
+	if want := []ocispec.Descriptor{target, config, app}; !reflect.DeepEqual(stream.Sent, want) {
+		t.Fatalf("sent blobs = %v, want %v", stream.Sent, want)
+	}
+	if want := target.Size + config.Size + app.Size; stream.Size != want {
+		t.Errorf("stream size = %d, want %d", stream.Size, want)
+	}
+	if want := base.Size + top.Size; stream.Reused != want {
+		t.Errorf("reused size = %d, want %d", stream.Reused, want)
+	}
+
+	var buf bytes.Buffer
+	if err := writeBlobArchive(context.Background(), &buf, source, image, stream.Sent); err != nil {
+		t.Fatal(err)
+	}
+	names, files := readArchive(t, &buf)
+
+	wantNames := []string{ocispec.ImageLayoutFile, blobPath(target), blobPath(config), blobPath(app), ocispec.ImageIndexFile}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("archive entries = %v, want %v", names, wantNames)
+	}
+	if want := []digest.Digest{target.Digest, config.Digest, app.Digest}; !reflect.DeepEqual(source.reads, want) {
+		t.Errorf("blobs read = %v, want %v", source.reads, want)
+	}
+	for _, desc := range stream.Sent {
+		if got := files[blobPath(desc)]; !bytes.Equal(got, source.blobs[desc.Digest]) {
+			t.Errorf("blob %s = %q, want %q", desc.Digest, got, source.blobs[desc.Digest])
+		}
+	}
+
+	var layout ocispec.ImageLayout
+	if err := json.Unmarshal(files[ocispec.ImageLayoutFile], &layout); err != nil {
+		t.Fatal(err)
+	}
+	if layout.Version != ocispec.ImageLayoutVersion {
+		t.Errorf("oci-layout version = %q, want %q", layout.Version, ocispec.ImageLayoutVersion)
+	}
+
+	var index ocispec.Index
+	if err := json.Unmarshal(files[ocispec.ImageIndexFile], &index); err != nil {
+		t.Fatal(err)
+	}
+	if index.SchemaVersion != 2 || index.MediaType != ocispec.MediaTypeImageIndex || len(index.Manifests) != 1 {
+		t.Fatalf("index.json = %+v, want a single manifest", index)
+	}
+	got := index.Manifests[0]
+	if got.Digest != target.Digest || got.MediaType != target.MediaType || got.Size != target.Size {
+		t.Errorf("index.json target = %+v, want %+v", got, target)
+	}
+	if name := got.Annotations[images.AnnotationImageName]; name != image.Ref {
+		t.Errorf("index.json image name = %q, want %q", name, image.Ref)
+	}
+	if image.Target.Annotations != nil {
+		t.Errorf("writeBlobArchive modified the image target: %v", image.Target.Annotations)
+	}
+}
+
+func TestWriteBlobArchiveShortBlob(t *testing.T) {
+	source := &memBlobSource{blobs: make(map[digest.Digest][]byte)}
+	image := testImage(t, source)
+	layer := image.Blobs[2]
+	source.blobs[layer.Digest] = source.blobs[layer.Digest][:2]
+
+	err := writeBlobArchive(context.Background(), io.Discard, source, image, image.Blobs)
+	if err == nil {
+		t.Fatal("writeBlobArchive with a truncated blob should fail")
+	}
+}
@@ -12,11 +12,12 @@ import (
 
 // MultiArchOptions 多架构同步选项
 type MultiArchOptions struct {
-	ImageName     string   // 镜像名称
-	Architectures []string // 架构列表 ["amd64", "arm64"]
-	OutputDir     string   // 输出目录
-	Cleanup       bool     // 是否清理临时文件
-	Verbose       bool     // 详细模式
+	ImageName     string       // 镜像名称
+	Architectures []string     // 架构列表 ["amd64", "arm64"]
+	OutputDir     string       // 输出目录
+	Cleanup       bool         // 是否清理临时文件
+	Verbose       bool         // 详细模式
+	Credentials   *Credentials // 镜像仓库凭据，nil 表示匿名拉取
 	ProgressCb    func(stage string, progress float64, message string)
 }
 
@@ -58,6 +59,7 @@ func PullAndPackMultiArch(ctx context.Context, opts MultiArchOptions) (*MultiArc
 		return nil, fmt.Errorf("创建 Docker 客户端失败: %w", err)
 	}
 	defer docker.Close()
+	docker.SetCredentials(opts.Credentials)
 
 	// 2. 并发拉取多架构镜像
 	progress("拉取", 0.1, fmt.Sprintf("开始拉取 %d 个架构的镜像...", len(opts.Architectures)))
@@ -0,0 +1,37 @@
+package imgsync
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+	"github.com/containerd/containerd/v2/core/remotes/docker/config"
+	"github.com/distribution/reference"
+)
+
+// registryHostsDir containerd 的镜像仓库配置目录 (hosts.toml 和证书)，与 CRI 插件使用同一目录
+const registryHostsDir = "/etc/containerd/certs.d"
+
+// newRegistryResolver 创建从镜像仓库拉取镜像的 resolver，creds 为 nil 时匿名拉取
+func newRegistryResolver(ctx context.Context, creds *Credentials) remotes.Resolver {
+	hostOpts := config.HostOptions{
+		HostDir: config.HostDirFromRoot(registryHostsDir),
+	}
+	if creds != nil {
+		hostOpts.Credentials = creds.ContainerdCredentials
+	}
+	return docker.NewResolver(docker.ResolverOptions{
+		Hosts: config.ConfigureHosts(ctx, hostOpts),
+	})
+}
+
+// normalizeImageRef 将 nginx:latest 这样的短名称补全为 docker.io/library/nginx:latest
+// containerd 只接受完整的镜像引用
+func normalizeImageRef(imageName string) (string, error) {
+	named, err := reference.ParseDockerRef(imageName)
+	if err != nil {
+		return "", fmt.Errorf("无效的镜像名称 '%s': %w", imageName, err)
+	}
+	return named.String(), nil
+}
@@ -2,15 +2,18 @@ package imgsync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/containerd/platforms"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
 	"golang.org/x/crypto/ssh/knownhosts"
@@ -24,25 +27,42 @@ type RemoteNode struct {
 	Namespace string // containerd namespace，默认 k8s.io
 }
 
+// ImageSource 提供要分发到远程节点的镜像 tar 流，DockerClient 和 ContainerdClient 都实现了该接口
+type ImageSource interface {
+	SaveToStream(ctx context.Context, imageName string) (io.ReadCloser, error)
+	GetImageSize(ctx context.Context, imageName string) (int64, error)
+}
+
 // ProgressCallback 进度回调函数
 type ProgressCallback func(node string, bytesWritten int64, totalBytes int64, percent float64)
 
 // DistributeOptions 分发选项
 type DistributeOptions struct {
 	Verbose    bool
-	ImageSize  int64 // 镜像大小（字节），用于计算进度百分比
+	ImageSize  int64  // 镜像大小（字节），用于计算进度百分比
+	Digest     string // 本地 containerd 中镜像的 digest，远程节点已有相同 digest 的镜像时跳过传输；为空时总是传输
 	ProgressCb ProgressCallback
 	SSHConfig  *ssh.ClientConfig
 }
 
+// NodeResult 一个远程节点的分发结果
+type NodeResult struct {
+	UpToDate    bool  // 节点已有相同 digest 的镜像，跳过了传输
+	Transferred int64 // 实际传输的字节数
+	Reused      int64 // 节点已有而没有传输的 layer 大小（字节）
+	Err         error // nil 表示成功
+}
+
 // DistributeToNodes 并行分发镜像到远程节点
-func DistributeToNodes(ctx context.Context, docker *DockerClient, imageName string, nodes []string, verbose bool) map[string]error {
+// digest 是本地 containerd 中镜像的 digest，远程节点已有该镜像时不再传输
+func DistributeToNodes(ctx context.Context, source ImageSource, imageName, digest string, nodes []string, verbose bool) map[string]NodeResult {
 	// 预先获取镜像大小
-	imageSize, _ := docker.GetImageSize(ctx, imageName)
+	imageSize, _ := source.GetImageSize(ctx, imageName)
 
 	opts := DistributeOptions{
 		Verbose:   verbose,
 		ImageSize: imageSize,
+		Digest:    digest,
 		ProgressCb: func(node string, written, total int64, pct float64) {
 			if verbose {
 				if total > 0 {
@@ -53,22 +73,22 @@ func DistributeToNodes(ctx context.Context, docker *DockerClient, imageName stri
 			}
 		},
 	}
-	return DistributeToNodesWithOptions(ctx, docker, imageName, nodes, opts)
+	return DistributeToNodesWithOptions(ctx, source, imageName, nodes, opts)
 }
 
 // DistributeToNodesWithOptions 带选项的并行分发
-func DistributeToNodesWithOptions(ctx context.Context, docker *DockerClient, imageName string, nodes []string, opts DistributeOptions) map[string]error {
+func DistributeToNodesWithOptions(ctx context.Context, source ImageSource, imageName string, nodes []string, opts DistributeOptions) map[string]NodeResult {
 	var wg sync.WaitGroup
-	results := make(map[string]error)
+	results := make(map[string]NodeResult)
 	var mu sync.Mutex
 
 	for _, node := range nodes {
 		wg.Add(1)
 		go func(n string) {
 			defer wg.Done()
-			err := distributeToNodeWithSSH(ctx, docker, imageName, n, opts)
+			result := distributeToNodeWithSSH(ctx, source, imageName, n, opts)
 			mu.Lock()
-			results[n] = err
+			results[n] = result
 			mu.Unlock()
 		}(node)
 	}
@@ -78,60 +98,106 @@ func DistributeToNodesWithOptions(ctx context.Context, docker *DockerClient, ima
 }
 
 // distributeToNodeWithSSH 使用纯 Go SSH 库分发镜像
-func distributeToNodeWithSSH(ctx context.Context, docker *DockerClient, imageName, node string, opts DistributeOptions) error {
+func distributeToNodeWithSSH(ctx context.Context, source ImageSource, imageName, node string, opts DistributeOptions) NodeResult {
 	if opts.Verbose {
 		fmt.Printf("[%s] 开始分发镜像 %s\n", node, imageName)
 	}
 
-	// 1. 获取镜像流
-	reader, err := docker.SaveToStream(ctx, imageName)
-	if err != nil {
-		return fmt.Errorf("获取镜像流失败: %w", err)
-	}
-	defer reader.Close()
-
-	// 2. 建立 SSH 连接
+	// 1. 建立 SSH 连接
 	sshConfig := opts.SSHConfig
 	if sshConfig == nil {
+		var err error
 		sshConfig, err = getDefaultSSHConfig()
 		if err != nil {
-			return fmt.Errorf("获取 SSH 配置失败: %w", err)
+			return NodeResult{Err: fmt.Errorf("获取 SSH 配置失败: %w", err)}
 		}
 	}
 
-	host, port := parseHostPort(node)
-	addr := net.JoinHostPort(host, port)
+	conn, err := dialNode(node, sshConfig, opts.Verbose)
+	if err != nil {
+		return NodeResult{Err: err}
+	}
+	defer conn.Close()
 
-	if opts.Verbose {
-		fmt.Printf("[%s] 连接到 %s\n", node, addr)
+	return distributeImage(ctx, conn, source, imageName, opts)
+}
+
+// distributeImage 通过已建立的连接将一个镜像分发到节点
+// 远程节点已有相同 digest 的镜像时跳过传输；来源实现了 BlobSource 时只传输远程节点缺少的 layer
+func distributeImage(ctx context.Context, conn *nodeConn, source ImageSource, imageName string, opts DistributeOptions) (result NodeResult) {
+	node := conn.node
+	fail := func(format string, args ...any) NodeResult {
+		result.Err = fmt.Errorf(format, args...)
+		return result
 	}
 
-	client, err := ssh.Dial("tcp", addr, sshConfig)
-	if err != nil {
-		return fmt.Errorf("SSH 连接失败: %w", err)
+	// 2. 比较远程节点上的镜像 digest，相同则跳过传输
+	if opts.Digest != "" {
+		if ref, err := normalizeImageRef(imageName); err == nil {
+			remoteDigest, err := conn.imageDigest(ref)
+			if err != nil && opts.Verbose {
+				fmt.Printf("[%s] 查询远程镜像失败，继续传输: %v\n", node, err)
+			}
+			if remoteDigest == opts.Digest {
+				if opts.Verbose {
+					fmt.Printf("[%s] 已有镜像 %s (%s)，跳过传输\n", node, ref, remoteDigest)
+				}
+				result.UpToDate = true
+				return result
+			}
+		}
+	}
+
+	// 3. 获取镜像流：优先只传输远程节点缺少的 layer，查询失败时回退到完整的镜像流
+	var reader io.ReadCloser
+	var stream *layerStream
+	totalBytes := opts.ImageSize
+	if blobs, ok := source.(BlobSource); ok {
+		var err error
+		stream, err = openLayerStream(ctx, conn, blobs, imageName)
+		switch {
+		case errors.Is(err, ErrPlatformMissing):
+			return fail("节点平台 %s: %w", conn.platformName, err)
+		case err != nil:
+			if opts.Verbose {
+				fmt.Printf("[%s] 无法按 layer 分发，传输完整镜像: %v\n", node, err)
+			}
+		default:
+			reader, totalBytes, result.Reused = stream, stream.Size, stream.Reused
+			if opts.Verbose {
+				fmt.Printf("[%s] 节点已有 %s 的 layer，需要传输 %s\n", node, formatBytes(stream.Reused), formatBytes(stream.Size))
+			}
+		}
+	}
+	if reader == nil {
+		var err error
+		reader, err = source.SaveToStream(ctx, imageName)
+		if err != nil {
+			return fail("获取镜像流失败: %w", err)
+		}
 	}
-	defer client.Close()
+	defer reader.Close()
 
-	// 3. 创建 session
-	session, err := client.NewSession()
+	// 4. 创建 session
+	session, err := conn.client.NewSession()
 	if err != nil {
-		return fmt.Errorf("创建 SSH session 失败: %w", err)
+		return fail("创建 SSH session 失败: %w", err)
 	}
 	defer session.Close()
 
-	// 4. 设置 stdin 管道
+	// 5. 设置 stdin 管道
 	stdin, err := session.StdinPipe()
 	if err != nil {
-		return fmt.Errorf("获取 stdin 管道失败: %w", err)
+		return fail("获取 stdin 管道失败: %w", err)
 	}
 
-	// 5. 启动远程命令
+	// 6. 启动远程命令
 	remoteCmd := "ctr -n k8s.io images import -"
 	if err := session.Start(remoteCmd); err != nil {
-		return fmt.Errorf("启动远程命令失败: %w", err)
+		return fail("启动远程命令失败: %w", err)
 	}
 
-	// 6. 带进度的流式传输
+	// 7. 带进度的流式传输
 	var copyErr error
 	var bytesWritten int64
 	done := make(chan struct{})
@@ -144,7 +210,7 @@ func distributeToNodeWithSSH(ctx context.Context, docker *DockerClient, imageNam
 		pw := &progressWriter{
 			writer:     stdin,
 			node:       node,
-			totalBytes: opts.ImageSize,
+			totalBytes: totalBytes,
 			cb:         opts.ProgressCb,
 		}
 
@@ -155,24 +221,104 @@ func distributeToNodeWithSSH(ctx context.Context, docker *DockerClient, imageNam
 		}
 	}()
 
-	// 7. 等待传输完成
+	// 8. 等待传输完成
 	<-done
 
-	// 8. 检查传输错误
+	// 9. 检查传输错误
+	result.Transferred = bytesWritten
 	if copyErr != nil {
-		return fmt.Errorf("流式传输失败: %w", copyErr)
+		return fail("流式传输失败: %w", copyErr)
 	}
 
-	// 9. 等待远程命令完成
+	// 10. 等待远程命令完成
 	if err := session.Wait(); err != nil {
-		return fmt.Errorf("远程命令执行失败: %w", err)
+		return fail("远程命令执行失败: %w", err)
+	}
+
+	// 导入成功后节点已有这些 blob，同一连接上的后续镜像不再传输
+	if stream != nil {
+		conn.addContent(stream.Sent)
 	}
 
 	if opts.Verbose {
 		fmt.Printf("[%s] 分发完成 ✓\n", node)
 	}
 
-	return nil
+	return result
+}
+
+// nodeConn 到一个远程节点的 SSH 连接，同一节点上的多个镜像共用一个连接，逐个分发
+type nodeConn struct {
+	node         string
+	client       *ssh.Client
+	platform     platforms.Matcher // 节点的平台，探测失败时为 nil，不按平台过滤
+	platformName string
+	content      map[string]bool // 节点内容存储中已有的 blob，nil 表示尚未查询
+}
+
+// dialNode 建立到节点的 SSH 连接，并通过 uname -m 探测节点的平台
+func dialNode(node string, sshConfig *ssh.ClientConfig, verbose bool) (*nodeConn, error) {
+	host, port := parseHostPort(node)
+	addr := net.JoinHostPort(host, port)
+
+	if verbose {
+		fmt.Printf("[%s] 连接到 %s\n", node, addr)
+	}
+
+	client, err := ssh.Dial("tcp", addr, sshConfig)
+	if err != nil {
+		return nil, fmt.Errorf("SSH 连接失败: %w", err)
+	}
+	conn := &nodeConn{node: node, client: client}
+
+	if out, err := conn.output("uname -m"); err == nil {
+		if spec, err := platforms.Parse("linux/" + strings.TrimSpace(string(out))); err == nil {
+			conn.platform = platforms.Only(spec)
+			conn.platformName = platforms.Format(spec)
+		}
+	}
+	if verbose && conn.platform == nil {
+		fmt.Printf("[%s] 无法探测节点平台，分发本地已有的全部平台\n", node)
+	}
+	return conn, nil
+}
+
+// Close 关闭连接
+func (c *nodeConn) Close() error {
+	return c.client.Close()
+}
+
+// output 在节点上执行命令并返回标准输出，每个命令使用一个新的 session
+func (c *nodeConn) output(cmd string) ([]byte, error) {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("创建 SSH session 失败: %w", err)
+	}
+	defer session.Close()
+	return session.Output(cmd)
+}
+
+// imageDigest 通过 ctr images ls 查询节点 k8s.io namespace 中镜像的 digest
+// 镜像不存在时返回空字符串
+func (c *nodeConn) imageDigest(ref string) (string, error) {
+	// 镜像引用经过 normalizeImageRef 校验，不包含引号和空白
+	out, err := c.output(fmt.Sprintf("ctr -n k8s.io images ls 'name==%s'", ref))
+	if err != nil {
+		return "", err
+	}
+	return parseImageListDigest(string(out), ref), nil
+}
+
+// parseImageListDigest 从 ctr images ls 的输出中取出 ref 对应的 DIGEST 列
+// 输出格式: REF TYPE DIGEST SIZE PLATFORMS LABELS
+func parseImageListDigest(output, ref string) string {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == ref {
+			return fields[2]
+		}
+	}
+	return ""
 }
 
 // progressWriter 带进度回调的 Writer
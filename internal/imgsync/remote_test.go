@@ -0,0 +1,79 @@
+package imgsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readFixture 读取 testdata 中保存的 ctr 命令输出
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseImageListDigest(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		ref    string
+		want   string
+	}{
+		{
+			name:   "image index with labels",
+			output: readFixture(t, "ctr_images_ls.txt"),
+			ref:    "docker.io/library/nginx:1.27",
+			want:   "sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+		},
+		{
+			name:   "ref that extends another ref",
+			output: readFixture(t, "ctr_images_ls.txt"),
+			ref:    "docker.io/library/nginx:1.27-alpine",
+			want:   "sha256:7b85f4b0a6e1b1e2f1b3c5a4b58d0e8d3a1f0b2c9d6e7f8a9b0c1d2e3f4a5b6c",
+		},
+		{
+			name:   "image without labels",
+			output: readFixture(t, "ctr_images_ls.txt"),
+			ref:    "registry.k8s.io/pause:3.10",
+			want:   "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			name:   "image not in list",
+			output: readFixture(t, "ctr_images_ls.txt"),
+			ref:    "docker.io/library/nginx:1.26",
+		},
+		{
+			name:   "prefix of a listed ref",
+			output: readFixture(t, "ctr_images_ls.txt"),
+			ref:    "docker.io/library/nginx",
+		},
+		{
+			name:   "header only",
+			output: readFixture(t, "ctr_images_ls_empty.txt"),
+			ref:    "docker.io/library/nginx:1.27",
+		},
+		{
+			name:   "empty output",
+			output: "",
+			ref:    "docker.io/library/nginx:1.27",
+		},
+		{
+			name:   "tabs and CRLF",
+			output: "REF\tTYPE\tDIGEST\tSIZE\tPLATFORMS\tLABELS\r\ndocker.io/library/redis:7\t\tapplication/vnd.oci.image.index.v1+json\tsha256:abc\t40.1 MiB\tlinux/amd64\t-\r\n",
+			ref:    "docker.io/library/redis:7",
+			want:   "sha256:abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseImageListDigest(tt.output, tt.ref); got != tt.want {
+				t.Errorf("parseImageListDigest(%q) = %q, want %q", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
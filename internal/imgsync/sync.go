@@ -8,29 +8,52 @@ import (
 	"time"
 )
 
+// 镜像来源
+const (
+	SourceDocker   = "docker"   // 通过 Docker daemon 拉取，再流式导入 containerd
+	SourceRegistry = "registry" // 通过 containerd 的 resolver 直接从镜像仓库拉取，不需要 Docker
+)
+
+// dockerImageIDLabel 记录本地 containerd 镜像导入自哪个 Docker 镜像 ID，ID 相同时跳过导入
+const dockerImageIDLabel = "k8s-toolkit/docker-image-id"
+
 // SyncOptions 同步选项
 type SyncOptions struct {
-	OutputDir  string   // 输出目录（仅用于传统模式）
-	Nodes      []string // 远程节点列表
-	Cleanup    bool     // 是否清理临时文件
-	Verbose    bool     // 详细模式
-	ProgressCb func(stage string, progress float64, message string)
+	Source      string       // 镜像来源: docker (默认) 或 registry
+	Credentials *Credentials // 镜像仓库凭据，nil 表示匿名拉取
+	OutputDir   string       // 输出目录（仅用于传统模式）
+	Nodes       []string     // 远程节点列表
+	Cleanup     bool         // 是否清理临时文件
+	Verbose     bool         // 详细模式
+	ProgressCb  func(stage string, progress float64, message string)
 }
 
 // SyncResult 同步结果
 type SyncResult struct {
 	ImageName     string
+	Digest        string // 本地 containerd 中镜像的 digest
 	LocalImported bool
-	RemoteNodes   map[string]error // 节点 -> 错误（nil 表示成功）
+	LocalUpToDate bool                  // 本地 containerd 已有相同镜像，跳过了导入
+	RemoteNodes   map[string]NodeResult // 节点 -> 分发结果
 	Duration      time.Duration
 }
 
-// SyncImage 流式同步镜像：Docker → Containerd
+// localImage 已导入本地 containerd 的镜像，分发时从本地 containerd 读取
+type localImage struct {
+	name     string // 镜像在 containerd 中的完整名称
+	digest   string // 本地 containerd 中镜像的 digest，用于与远程节点比较
+	upToDate bool
+}
+
+// progressFunc 同步过程中各阶段的进度回调
+type progressFunc func(stage string, pct float64, msg string)
+
+// SyncImage 流式同步镜像：Docker → Containerd，或镜像仓库 → Containerd
 func SyncImage(ctx context.Context, imageName string, opts SyncOptions) (*SyncResult, error) {
 	startTime := time.Now()
 	result := &SyncResult{
 		ImageName:   imageName,
-		RemoteNodes: make(map[string]error),
+		RemoteNodes: make(map[string]NodeResult),
 	}
 
 	// 进度回调封装
@@ -40,73 +63,215 @@ func SyncImage(ctx context.Context, imageName string, opts SyncOptions) (*SyncRe
 		}
 	}
 
-	// 1. 创建 Docker 客户端
-	progress("初始化", 0, "创建 Docker 客户端...")
-	docker, err := NewDockerClient()
+	// 1. 创建客户端
+	clients, err := newSyncClients(opts.Source, opts.Credentials, progress)
 	if err != nil {
-		return nil, fmt.Errorf("创建 Docker 客户端失败: %w", err)
+		return nil, err
 	}
-	defer docker.Close()
+	defer clients.Close()
 
-	// 2. 拉取镜像
-	progress("拉取", 0.1, fmt.Sprintf("正在拉取镜像 %s...", imageName))
-	pullCb := func(p PullProgress) {
-		if opts.Verbose && p.Status != "" {
-			msg := p.Status
-			if p.Progress != "" {
-				msg += " " + p.Progress
+	// 2-4. 拉取镜像并导入本地 containerd
+	local, err := clients.pull(ctx, imageName, nil, opts.Verbose, progress)
+	if err != nil {
+		return nil, err
+	}
+	result.Digest = local.digest
+	result.LocalImported = !local.upToDate
+	result.LocalUpToDate = local.upToDate
+
+	// 5. 远程节点分发（如果有），已有相同 digest 镜像的节点跳过传输
+	if len(opts.Nodes) > 0 {
+		progress("分发", 0.8, fmt.Sprintf("正在分发到 %d 个远程节点...", len(opts.Nodes)))
+		result.RemoteNodes = DistributeToNodes(ctx, clients.ctrd, local.name, local.digest, opts.Nodes, opts.Verbose)
+
+		synced, upToDate := 0, 0
+		for _, r := range result.RemoteNodes {
+			switch {
+			case r.Err != nil:
+			case r.UpToDate:
+				upToDate++
+			default:
+				synced++
 			}
-			progress("拉取", 0.1, msg)
 		}
+		progress("分发", 1.0, fmt.Sprintf("分发完成: %d 个同步, %d 个已是最新, %d 个失败",
+			synced, upToDate, len(opts.Nodes)-synced-upToDate))
 	}
-	if err := docker.Pull(ctx, imageName, pullCb); err != nil {
-		return nil, fmt.Errorf("拉取镜像失败: %w", err)
+
+	result.Duration = time.Since(startTime)
+	progress("完成", 1.0, fmt.Sprintf("总耗时: %v", result.Duration))
+
+	return result, nil
+}
+
+// syncClients 同步使用的客户端，多个镜像共用；Docker 来源时 docker 不为 nil
+type syncClients struct {
+	docker *DockerClient
+	ctrd   *ContainerdClient
+}
+
+// newSyncClients 根据镜像来源创建客户端
+func newSyncClients(source string, creds *Credentials, progress progressFunc) (*syncClients, error) {
+	clients := &syncClients{}
+	switch source {
+	case SourceDocker, "":
+		progress("初始化", 0, "创建 Docker 客户端...")
+		docker, err := NewDockerClient()
+		if err != nil {
+			return nil, fmt.Errorf("创建 Docker 客户端失败: %w", err)
+		}
+		docker.SetCredentials(creds)
+		clients.docker = docker
+	case SourceRegistry:
+	default:
+		return nil, fmt.Errorf("不支持的镜像来源: %s (可选: %s, %s)", source, SourceDocker, SourceRegistry)
 	}
-	progress("拉取", 0.4, "镜像拉取完成")
 
-	// 3. 创建 Containerd 客户端
-	progress("初始化", 0.4, "创建 Containerd 客户端...")
+	progress("初始化", 0, "创建 Containerd 客户端...")
 	ctrd, err := NewContainerdClient(DefaultContainerdOptions())
 	if err != nil {
+		clients.Close()
 		return nil, fmt.Errorf("创建 Containerd 客户端失败: %w", err)
 	}
-	defer ctrd.Close()
+	ctrd.SetCredentials(creds)
+	clients.ctrd = ctrd
+	return clients, nil
+}
 
+// Close 关闭客户端
+func (c *syncClients) Close() {
+	if c.docker != nil {
+		c.docker.Close()
+	}
+	if c.ctrd != nil {
+		c.ctrd.Close()
+	}
+}
+
+// pull 拉取镜像并导入本地 containerd，platformList 为空时拉取本机平台
+func (c *syncClients) pull(ctx context.Context, imageName string, platformList []string, verbose bool, progress progressFunc) (*localImage, error) {
+	if c.docker == nil {
+		return syncFromRegistry(ctx, c.ctrd, imageName, platformList, verbose, progress)
+	}
+
+	// Docker 的镜像存储中一个标签只对应一个平台
+	var platform string
+	switch len(platformList) {
+	case 0:
+	case 1:
+		platform = platformList[0]
+	default:
+		return nil, fmt.Errorf("使用 Docker 拉取时每个镜像只能指定一个平台，多个平台请使用 --source %s", SourceRegistry)
+	}
+	return syncFromDocker(ctx, c.docker, c.ctrd, imageName, platform, verbose, progress)
+}
+
+// syncFromDocker 通过 Docker 拉取镜像并流式导入本地 containerd
+// 本地 containerd 镜像记录的 Docker 镜像 ID 与拉取结果相同时跳过导入
+func syncFromDocker(ctx context.Context, docker *DockerClient, ctrd *ContainerdClient, imageName, platform string, verbose bool, progress progressFunc) (*localImage, error) {
+	// 2. 拉取镜像
+	progress("拉取", 0.1, fmt.Sprintf("正在拉取镜像 %s...", imageName))
+	if err := docker.Pull(ctx, imageName, platform, pullProgress(verbose, progress)); err != nil {
+		return nil, fmt.Errorf("拉取镜像失败: %w", err)
+	}
+	progress("拉取", 0.4, "镜像拉取完成")
+
+	imageID, err := docker.ImageID(ctx, imageName)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := normalizeImageRef(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	local := &localImage{name: ref}
+
+	// 3. 本地 containerd 已从同一个 Docker 镜像导入过时不再导入
+	importedID, err := ctrd.ImageLabel(ctx, ref, dockerImageIDLabel)
+	if err != nil {
+		return nil, err
+	}
+	if importedID == imageID {
+		local.upToDate = true
+		progress("同步", 0.8, fmt.Sprintf("本地 containerd 已是最新: %s", ref))
+	} else if err := importFromDocker(ctx, docker, ctrd, imageName, progress); err != nil {
+		return nil, err
+	} else if err := ctrd.SetImageLabel(ctx, ref, dockerImageIDLabel, imageID); err != nil {
+		// 标签只用于下次跳过导入，设置失败不影响本次同步
+		progress("同步", 0.8, fmt.Sprintf("记录 Docker 镜像 ID 失败: %v", err))
+	}
+
+	local.digest, err = ctrd.ImageDigest(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return local, nil
+}
+
+// importFromDocker 将镜像从 Docker 流式导入 containerd
+func importFromDocker(ctx context.Context, docker *DockerClient, ctrd *ContainerdClient, imageName string, progress progressFunc) error {
 	// 4. 流式传输：Docker → Containerd（核心优化点）
 	progress("同步", 0.5, "正在流式传输镜像到 Containerd...")
 	reader, err := docker.SaveToStream(ctx, imageName)
 	if err != nil {
-		return nil, fmt.Errorf("获取镜像流失败: %w", err)
+		return fmt.Errorf("获取镜像流失败: %w", err)
 	}
 	defer reader.Close()
 
 	// 直接导入到 containerd（无临时文件）
 	imported, err := ctrd.ImportFromStream(ctx, reader)
 	if err != nil {
-		return nil, fmt.Errorf("导入到 Containerd 失败: %w", err)
+		return fmt.Errorf("导入到 Containerd 失败: %w", err)
 	}
-	result.LocalImported = true
 	progress("同步", 0.8, fmt.Sprintf("本地导入完成: %v", imported))
+	return nil
+}
 
-	// 5. 远程节点分发（如果有）
-	if len(opts.Nodes) > 0 {
-		progress("分发", 0.8, fmt.Sprintf("正在分发到 %d 个远程节点...", len(opts.Nodes)))
-		nodeErrors := DistributeToNodes(ctx, docker, imageName, opts.Nodes, opts.Verbose)
-		result.RemoteNodes = nodeErrors
-
-		successCount := 0
-		for _, err := range nodeErrors {
-			if err == nil {
-				successCount++
-			}
+// syncFromRegistry 通过 containerd 直接从镜像仓库拉取镜像并解包
+// containerd 只下载内容存储中缺少的 blob，拉取前后 digest 相同说明本地已是最新
+func syncFromRegistry(ctx context.Context, ctrd *ContainerdClient, imageName string, platformList []string, verbose bool, progress progressFunc) (*localImage, error) {
+	var previous string
+	if ref, err := normalizeImageRef(imageName); err == nil {
+		if previous, err = ctrd.ImageDigest(ctx, ref); err != nil {
+			return nil, err
 		}
-		progress("分发", 1.0, fmt.Sprintf("分发完成: %d/%d 成功", successCount, len(opts.Nodes)))
 	}
 
-	result.Duration = time.Since(startTime)
-	progress("完成", 1.0, fmt.Sprintf("总耗时: %v", result.Duration))
+	progress("拉取", 0.1, fmt.Sprintf("正在从镜像仓库拉取镜像 %s...", imageName))
+	name, err := ctrd.Pull(ctx, imageName, platformList, pullProgress(verbose, progress))
+	if err != nil {
+		return nil, fmt.Errorf("拉取镜像失败: %w", err)
+	}
+	progress("拉取", 0.4, "镜像拉取完成")
 
-	return result, nil
+	digest, err := ctrd.ImageDigest(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	local := &localImage{name: name, digest: digest}
+	if previous == digest {
+		local.upToDate = true
+		progress("同步", 0.8, fmt.Sprintf("本地 containerd 已是最新: %s", name))
+	} else {
+		// 镜像已直接写入 containerd 的内容存储并解包，不需要导入
+		progress("同步", 0.8, fmt.Sprintf("本地导入完成: [%s]", name))
+	}
+	return local, nil
+}
+
+// pullProgress 将拉取进度转换为 "拉取" 阶段的回调，只在详细模式下输出
+func pullProgress(verbose bool, progress progressFunc) func(PullProgress) {
+	return func(p PullProgress) {
+		if verbose && p.Status != "" {
+			msg := p.Status
+			if p.Progress != "" {
+				msg += " " + p.Progress
+			}
+			progress("拉取", 0.1, msg)
+		}
+	}
 }
 
 // StreamSync 执行流式同步（高级 API，支持自定义 Reader/Writer）
@@ -0,0 +1,43 @@
+package kube
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ClientOptions 创建 Kubernetes 客户端的参数
+type ClientOptions struct {
+	Kubeconfig string // kubeconfig 路径，为空时使用 KUBECONFIG 环境变量或 ~/.kube/config
+	Context    string // kubeconfig 中的上下文，为空时使用 current-context
+}
+
+// Client 封装 Kubernetes API 客户端
+type Client struct {
+	clientset kubernetes.Interface
+	config    *rest.Config
+}
+
+// NewClient 按 kubectl 相同的规则加载 kubeconfig 并创建客户端
+// 找不到 kubeconfig 时回退到集群内 ServiceAccount 配置
+func NewClient(opts ClientOptions) (*Client, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if opts.Kubeconfig != "" {
+		rules.ExplicitPath = opts.Kubeconfig
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: opts.Context}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("加载 kubeconfig 失败: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("创建 Kubernetes 客户端失败: %w", err)
+	}
+
+	return &Client{clientset: clientset, config: config}, nil
+}
@@ -0,0 +1,184 @@
+package kube
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ResolveContainer 在 Pod 中选择目标容器
+// 索引以 spec.containers 的声明顺序为准，容器状态按名称匹配，
+// 避免 status.containerStatuses 排序与声明顺序不一致导致选错容器。
+// 按名称选择时同时查找 init 容器和 ephemeral 容器
+func ResolveContainer(pod *corev1.Pod, sel ContainerSelector) (*Container, error) {
+	containers := ListContainers(pod)
+
+	if sel.Name != "" {
+		for i := range containers {
+			if containers[i].Name == sel.Name {
+				return &containers[i], nil
+			}
+		}
+		return nil, fmt.Errorf("%w: 容器 '%s' 不在 Pod %s/%s 中，可用的容器: %s",
+			ErrContainerNotFound, sel.Name, pod.Namespace, pod.Name, strings.Join(ContainerNames(pod), " "))
+	}
+
+	specs := pod.Spec.Containers
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("%w: Pod %s/%s 中没有容器", ErrContainerNotFound, pod.Namespace, pod.Name)
+	}
+	if sel.Index < 0 || sel.Index >= len(specs) {
+		return nil, fmt.Errorf("%w: 容器索引 %d 超出范围 (0-%d)，可用的容器: %s",
+			ErrContainerNotFound, sel.Index, len(specs)-1, strings.Join(ContainerNames(pod), " "))
+	}
+	// ListContainers 中普通容器排在最前面，顺序与 spec.containers 一致
+	return &containers[sel.Index], nil
+}
+
+// ListContainers 返回 Pod 中所有容器及其状态
+// 顺序为: spec.containers、spec.initContainers、spec.ephemeralContainers
+func ListContainers(pod *corev1.Pod) []Container {
+	var containers []Container
+
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, Container{
+			Pod:    pod,
+			Name:   c.Name,
+			Kind:   ContainerKindRegular,
+			Status: findStatus(pod.Status.ContainerStatuses, c.Name),
+		})
+	}
+	for _, c := range pod.Spec.InitContainers {
+		containers = append(containers, Container{
+			Pod:    pod,
+			Name:   c.Name,
+			Kind:   ContainerKindInit,
+			Status: findStatus(pod.Status.InitContainerStatuses, c.Name),
+		})
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		containers = append(containers, Container{
+			Pod:    pod,
+			Name:   c.Name,
+			Kind:   ContainerKindEphemeral,
+			Status: findStatus(pod.Status.EphemeralContainerStatuses, c.Name),
+		})
+	}
+
+	return containers
+}
+
+// ContainerNames 返回 Pod 中所有容器的名称，顺序与 ListContainers 一致
+func ContainerNames(pod *corev1.Pod) []string {
+	containers := ListContainers(pod)
+	names := make([]string, 0, len(containers))
+	for _, c := range containers {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// CheckRunning 检查容器是否处于运行状态
+// init 容器运行时 Pod 仍处于 Pending 阶段，因此以容器自身的状态为准
+func (c *Container) CheckRunning() error {
+	if c.Status != nil && c.Status.State.Running != nil {
+		return nil
+	}
+
+	switch c.Pod.Status.Phase {
+	case corev1.PodSucceeded, corev1.PodFailed:
+		return fmt.Errorf("%w: %s/%s 当前状态为 %s", ErrPodNotRunning, c.Pod.Namespace, c.Pod.Name, c.Pod.Status.Phase)
+	}
+
+	if c.Status == nil {
+		return fmt.Errorf("%w: %s '%s' 尚未上报状态", ErrContainerNotRunning, c.kindName(), c.Name)
+	}
+
+	state := c.Status.State
+	if state.Terminated != nil {
+		t := state.Terminated
+		return fmt.Errorf("%w: %s '%s' 已退出 (原因: %s, 退出码: %d, 结束于: %s)",
+			ErrContainerNotRunning, c.kindName(), c.Name, t.Reason, t.ExitCode,
+			t.FinishedAt.Format("2006-01-02 15:04:05"))
+	}
+	return fmt.Errorf("%w: %s '%s' 当前状态为 %s", ErrContainerNotRunning, c.kindName(), c.Name, containerStateName(state))
+}
+
+// ContainerID 返回容器 ID 前缀中的运行时名称以及去掉前缀的 ID
+func (c *Container) ContainerID() (runtime, id string) {
+	if c.Status == nil {
+		return "", ""
+	}
+	return ParseContainerID(c.Status.ContainerID)
+}
+
+// SecurityContext 返回容器 spec 中的 securityContext，未设置时返回 nil
+func (c *Container) SecurityContext() *corev1.SecurityContext {
+	switch c.Kind {
+	case ContainerKindInit:
+		for i := range c.Pod.Spec.InitContainers {
+			if c.Pod.Spec.InitContainers[i].Name == c.Name {
+				return c.Pod.Spec.InitContainers[i].SecurityContext
+			}
+		}
+	case ContainerKindEphemeral:
+		for i := range c.Pod.Spec.EphemeralContainers {
+			if c.Pod.Spec.EphemeralContainers[i].Name == c.Name {
+				return c.Pod.Spec.EphemeralContainers[i].SecurityContext
+			}
+		}
+	default:
+		for i := range c.Pod.Spec.Containers {
+			if c.Pod.Spec.Containers[i].Name == c.Name {
+				return c.Pod.Spec.Containers[i].SecurityContext
+			}
+		}
+	}
+	return nil
+}
+
+// kindName 返回容器类型的中文名称，用于错误信息
+func (c *Container) kindName() string {
+	switch c.Kind {
+	case ContainerKindInit:
+		return "init 容器"
+	case ContainerKindEphemeral:
+		return "ephemeral 容器"
+	default:
+		return "容器"
+	}
+}
+
+// ParseContainerID 拆分 "containerd://abc" 格式的容器 ID
+// 返回运行时名称和去掉前缀的 ID
+func ParseContainerID(raw string) (runtime, id string) {
+	if idx := strings.Index(raw, "://"); idx >= 0 {
+		return raw[:idx], raw[idx+3:]
+	}
+	return "", raw
+}
+
+// findStatus 按名称查找容器状态
+func findStatus(statuses []corev1.ContainerStatus, name string) *corev1.ContainerStatus {
+	for i := range statuses {
+		if statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+// containerStateName 返回容器状态的可读名称
+func containerStateName(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "running"
+	case state.Waiting != nil:
+		return "waiting (" + state.Waiting.Reason + ")"
+	case state.Terminated != nil:
+		return "terminated (" + state.Terminated.Reason + ")"
+	default:
+		return "unknown"
+	}
+}
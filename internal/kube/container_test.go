@@ -0,0 +1,29 @@
+package kube
+
+import "testing"
+
+func TestParseContainerID(t *testing.T) {
+	tests := []struct {
+		raw         string
+		wantRuntime string
+		wantID      string
+	}{
+		{raw: "containerd://4f1c2a9e8b7d", wantRuntime: "containerd", wantID: "4f1c2a9e8b7d"},
+		{raw: "docker://9a8b7c6d5e4f", wantRuntime: "docker", wantID: "9a8b7c6d5e4f"},
+		{raw: "cri-o://0123456789ab", wantRuntime: "cri-o", wantID: "0123456789ab"},
+		{raw: "4f1c2a9e8b7d", wantRuntime: "", wantID: "4f1c2a9e8b7d"},
+		{raw: "", wantRuntime: "", wantID: ""},
+		{raw: "containerd://", wantRuntime: "containerd", wantID: ""},
+		{raw: "://4f1c2a9e8b7d", wantRuntime: "", wantID: "4f1c2a9e8b7d"},
+		{raw: "containerd:/4f1c2a9e8b7d", wantRuntime: "", wantID: "containerd:/4f1c2a9e8b7d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			runtime, id := ParseContainerID(tt.raw)
+			if runtime != tt.wantRuntime || id != tt.wantID {
+				t.Errorf("ParseContainerID(%q) = (%q, %q), want (%q, %q)", tt.raw, runtime, id, tt.wantRuntime, tt.wantID)
+			}
+		})
+	}
+}
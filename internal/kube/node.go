@@ -0,0 +1,28 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetNodeInternalIP 获取节点的 InternalIP
+func (c *Client) GetNodeInternalIP(ctx context.Context, name string) (string, error) {
+	node, err := c.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("获取节点 %s 失败: %w", name, err)
+	}
+	return nodeInternalIP(node)
+}
+
+// nodeInternalIP 从节点状态中取出 InternalIP
+func nodeInternalIP(node *corev1.Node) (string, error) {
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == corev1.NodeInternalIP {
+			return addr.Address, nil
+		}
+	}
+	return "", fmt.Errorf("节点 %s 没有 InternalIP 地址", node.Name)
+}
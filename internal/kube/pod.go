@@ -0,0 +1,85 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetPod 获取 Pod 对象
+func (c *Client) GetPod(ctx context.Context, namespace, name string) (*corev1.Pod, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s/%s", ErrPodNotFound, namespace, name)
+		}
+		return nil, fmt.Errorf("获取 Pod %s/%s 失败: %w", namespace, name, err)
+	}
+	return pod, nil
+}
+
+// ListPodNames 获取命名空间下所有 Pod 的名称
+func (c *Client) ListPodNames(ctx context.Context, namespace string) ([]string, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("列出命名空间 %s 中的 Pod 失败: %w", namespace, err)
+	}
+
+	names := make([]string, 0, len(pods.Items))
+	for _, p := range pods.Items {
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
+
+// ListNamespaces 获取所有命名空间的名称
+func (c *Client) ListNamespaces(ctx context.Context) ([]string, error) {
+	list, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("列出命名空间失败: %w", err)
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		names = append(names, ns.Name)
+	}
+	return names, nil
+}
+
+// ListRunningPods 按标签选择器列出处于 Running 状态的 Pod，按名称排序
+// 有 Pod 匹配但都不在运行时返回 ErrPodNotRunning，并列出各 Pod 的状态
+func (c *Client) ListRunningPods(ctx context.Context, namespace, selector string) ([]corev1.Pod, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("按选择器 '%s' 列出 Pod 失败: %w", selector, err)
+	}
+	if len(pods.Items) == 0 {
+		return nil, fmt.Errorf("%w: 命名空间 %s 中没有匹配 '%s' 的 Pod", ErrPodNotFound, namespace, selector)
+	}
+
+	var running []corev1.Pod
+	var others []string
+	for _, p := range pods.Items {
+		if p.Status.Phase == corev1.PodRunning && p.DeletionTimestamp == nil {
+			running = append(running, p)
+			continue
+		}
+		phase := string(p.Status.Phase)
+		if p.DeletionTimestamp != nil {
+			phase = "Terminating"
+		}
+		others = append(others, fmt.Sprintf("%s(%s)", p.Name, phase))
+	}
+	if len(running) == 0 {
+		return nil, fmt.Errorf("%w: 匹配 '%s' 的 %d 个 Pod 都未运行: %s",
+			ErrPodNotRunning, selector, len(pods.Items), strings.Join(others, " "))
+	}
+
+	sort.Slice(running, func(i, j int) bool { return running[i].Name < running[j].Name })
+	return running, nil
+}
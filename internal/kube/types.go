@@ -0,0 +1,39 @@
+package kube
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 常见错误，调用方可以使用 errors.Is 判断
+var (
+	ErrPodNotFound         = errors.New("pod 不存在")
+	ErrPodNotRunning       = errors.New("pod 未处于 Running 状态")
+	ErrContainerNotFound   = errors.New("容器不存在")
+	ErrContainerNotRunning = errors.New("容器未运行")
+)
+
+// ContainerKind 容器类型
+type ContainerKind string
+
+const (
+	ContainerKindRegular   ContainerKind = "container" // spec.containers
+	ContainerKindInit      ContainerKind = "init"      // spec.initContainers
+	ContainerKindEphemeral ContainerKind = "ephemeral" // spec.ephemeralContainers (kubectl debug)
+)
+
+// ContainerSelector 选择 Pod 中的容器
+// Name 非空时按名称在所有类型的容器中选择，否则按 spec.containers 中的索引选择
+type ContainerSelector struct {
+	Name  string
+	Index int
+}
+
+// Container 表示解析后的容器
+type Container struct {
+	Pod    *corev1.Pod
+	Name   string
+	Kind   ContainerKind
+	Status *corev1.ContainerStatus // 容器尚未上报状态时为 nil
+}
@@ -0,0 +1,73 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsWorkloadRef 判断 -p 参数是否为 "deploy/foo" 形式的工作负载引用
+func IsWorkloadRef(ref string) bool {
+	return strings.Contains(ref, "/")
+}
+
+// WorkloadSelector 返回工作负载的 Pod 标签选择器
+// 支持 deploy/NAME、sts/NAME、ds/NAME、rs/NAME 以及对应的全称和复数形式；
+// pod/NAME 形式返回空选择器和 Pod 名称
+func (c *Client) WorkloadSelector(ctx context.Context, namespace, ref string) (selector, podName string, err error) {
+	kind, name, _ := strings.Cut(ref, "/")
+	if name == "" {
+		return "", "", fmt.Errorf("无效的工作负载引用: %s (格式: deploy/NAME)", ref)
+	}
+
+	var labelSelector *metav1.LabelSelector
+	apps := c.clientset.AppsV1()
+	switch strings.ToLower(kind) {
+	case "po", "pod", "pods":
+		return "", name, nil
+	case "deploy", "deployment", "deployments":
+		obj, e := apps.Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if e == nil {
+			labelSelector = obj.Spec.Selector
+		}
+		err = e
+	case "sts", "statefulset", "statefulsets":
+		obj, e := apps.StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if e == nil {
+			labelSelector = obj.Spec.Selector
+		}
+		err = e
+	case "ds", "daemonset", "daemonsets":
+		obj, e := apps.DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if e == nil {
+			labelSelector = obj.Spec.Selector
+		}
+		err = e
+	case "rs", "replicaset", "replicasets":
+		obj, e := apps.ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if e == nil {
+			labelSelector = obj.Spec.Selector
+		}
+		err = e
+	default:
+		return "", "", fmt.Errorf("不支持的资源类型: %s (可选: deploy, sts, ds, rs, pod)", kind)
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", "", fmt.Errorf("%s/%s 不存在于命名空间 %s", kind, name, namespace)
+		}
+		return "", "", fmt.Errorf("获取 %s 失败: %w", ref, err)
+	}
+
+	sel, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return "", "", fmt.Errorf("解析 %s 的标签选择器失败: %w", ref, err)
+	}
+	if sel.Empty() {
+		return "", "", fmt.Errorf("%s 没有标签选择器", ref)
+	}
+	return sel.String(), "", nil
+}
@@ -0,0 +1,79 @@
+package multiexec
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+)
+
+// RunInteractive 在单个节点上执行 opts.Command 并接管本地终端，返回远程命令的退出码
+// 本地标准输入是终端时会申请 PTY 并切换到 raw 模式，否则按普通流式输入输出执行
+func RunInteractive(node string, opts ExecOptions, sshConfig *ssh.ClientConfig) (int, error) {
+	host, port := parseHostPort(node, opts.Port)
+	addr := net.JoinHostPort(host, port)
+
+	client, err := ssh.Dial("tcp", addr, sshConfig)
+	if err != nil {
+		return 0, fmt.Errorf("SSH 连接失败: %w", err)
+	}
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return 0, fmt.Errorf("创建 SSH session 失败: %w", err)
+	}
+	defer session.Close()
+
+	session.Stdin = os.Stdin
+	session.Stdout = os.Stdout
+	session.Stderr = os.Stderr
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		width, height, err := term.GetSize(fd)
+		if err != nil {
+			width, height = 80, 24
+		}
+		termType := os.Getenv("TERM")
+		if termType == "" {
+			termType = "xterm-256color"
+		}
+
+		modes := ssh.TerminalModes{
+			ssh.ECHO:          1,
+			ssh.TTY_OP_ISPEED: 14400,
+			ssh.TTY_OP_OSPEED: 14400,
+		}
+		if err := session.RequestPty(termType, height, width, modes); err != nil {
+			return 0, fmt.Errorf("申请 PTY 失败: %w", err)
+		}
+
+		oldState, err := term.MakeRaw(fd)
+		if err != nil {
+			return 0, fmt.Errorf("设置终端 raw 模式失败: %w", err)
+		}
+		defer term.Restore(fd, oldState)
+
+		stop := watchWindowSize(fd, session)
+		defer stop()
+	}
+
+	// 构造命令
+	cmd := opts.Command
+	if opts.Sudo {
+		cmd = "sudo " + cmd
+	}
+
+	if err := session.Run(cmd); err != nil {
+		var exitErr *ssh.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitStatus(), nil
+		}
+		return 0, fmt.Errorf("远程命令执行失败: %w", err)
+	}
+	return 0, nil
+}
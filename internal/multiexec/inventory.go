@@ -0,0 +1,44 @@
+package multiexec
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadInventory 读取节点清单文件，返回节点名称到 SSH 地址的映射
+//
+// 文件格式为每行一个节点，"#" 开头的行为注释:
+//
+//	node-1  10.0.0.11
+//	node-2  10.0.0.12:2222
+func LoadInventory(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开节点清单失败: %w", err)
+	}
+	defer file.Close()
+
+	inventory := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("节点清单 %s 第 %d 行格式错误，应为 \"<节点名> <地址[:端口]>\"", path, lineNo)
+		}
+		inventory[fields[0]] = fields[1]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取节点清单失败: %w", err)
+	}
+	return inventory, nil
+}
@@ -0,0 +1,10 @@
+package multiexec
+
+import (
+	"golang.org/x/crypto/ssh"
+)
+
+// watchWindowSize Windows 没有 SIGWINCH，不同步窗口大小
+func watchWindowSize(fd int, session *ssh.Session) func() {
+	return func() {}
+}
@@ -0,0 +1,115 @@
+package nsenter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/trynocoding/k8s-toolkit/internal/kube"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// DefaultCRISockets 未指定 CRI socket 时依次探测的地址
+var DefaultCRISockets = []string{
+	"/run/containerd/containerd.sock",
+	"/run/crio/crio.sock",
+	"/var/run/cri-dockerd.sock",
+}
+
+// criResolver 通过 CRI RuntimeService 的 ContainerStatus 获取容器 PID（等价于 crictl inspect）
+type criResolver struct {
+	conn    *grpc.ClientConn
+	client  runtimeapi.RuntimeServiceClient
+	socket  string
+	runtime string // 运行时上报的名称，如 containerd、cri-o
+}
+
+func newCRIResolver(ctx context.Context, socket string) (*criResolver, error) {
+	sockets := DefaultCRISockets
+	if socket != "" {
+		sockets = []string{socket}
+	}
+
+	var errs []string
+	for _, s := range sockets {
+		r, err := dialCRI(ctx, s)
+		if err == nil {
+			return r, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return nil, fmt.Errorf("%w: 无法连接 CRI 运行时 (%s)", ErrRuntimeUnavailable, strings.Join(errs, "; "))
+}
+
+// dialCRI 连接指定的 CRI socket 并通过 Version 调用确认服务可用
+func dialCRI(ctx context.Context, socket string) (*criResolver, error) {
+	path := strings.TrimPrefix(socket, "unix://")
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("socket 不存在: %s", path)
+	}
+
+	conn, err := grpc.NewClient("unix://"+path, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("连接 %s 失败: %v", path, err)
+	}
+
+	client := runtimeapi.NewRuntimeServiceClient(conn)
+
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	version, err := client.Version(probeCtx, &runtimeapi.VersionRequest{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%s 不是可用的 CRI 服务: %v", path, err)
+	}
+
+	return &criResolver{
+		conn:    conn,
+		client:  client,
+		socket:  path,
+		runtime: version.RuntimeName,
+	}, nil
+}
+
+func (r *criResolver) Name() string {
+	return fmt.Sprintf("cri (%s, %s)", r.runtime, r.socket)
+}
+
+func (r *criResolver) ContainerPID(ctx context.Context, containerID string) (int, error) {
+	resp, err := r.client.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{
+		ContainerId: containerID,
+		Verbose:     true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("CRI ContainerStatus 调用失败 (容器 '%s'): %w", containerID, err)
+	}
+
+	if resp.Status != nil && resp.Status.State != runtimeapi.ContainerState_CONTAINER_RUNNING {
+		return 0, fmt.Errorf("%w: 容器 '%s' 当前状态为 %s", kube.ErrContainerNotRunning, containerID, resp.Status.State)
+	}
+
+	// containerd 与 CRI-O 都在 verbose 信息的 "info" 字段中以 JSON 形式给出 pid
+	raw, ok := resp.Info["info"]
+	if !ok {
+		return 0, fmt.Errorf("CRI 运行时未返回容器 '%s' 的详细信息", containerID)
+	}
+	var info struct {
+		Pid int `json:"pid"`
+	}
+	if err := json.Unmarshal([]byte(raw), &info); err != nil {
+		return 0, fmt.Errorf("解析容器 '%s' 的详细信息失败: %w", containerID, err)
+	}
+
+	if info.Pid == 0 {
+		return 0, fmt.Errorf("%w: 容器 '%s' 的 PID 无效", kube.ErrContainerNotRunning, containerID)
+	}
+	return info.Pid, nil
+}
+
+func (r *criResolver) Close() error {
+	return r.conn.Close()
+}
@@ -0,0 +1,157 @@
+package nsenter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// namespaceFlags /proc/<pid>/ns 下的命名空间文件与 setns 类型的对应关系
+var namespaceFlags = map[string]int{
+	"user":   unix.CLONE_NEWUSER,
+	"cgroup": unix.CLONE_NEWCGROUP,
+	"ipc":    unix.CLONE_NEWIPC,
+	"uts":    unix.CLONE_NEWUTS,
+	"net":    unix.CLONE_NEWNET,
+	"pid":    unix.CLONE_NEWPID,
+	"mnt":    unix.CLONE_NEWNS,
+}
+
+// JoinNamespaces 让当前线程加入目标进程的命名空间，names 需先经过 ParseNamespaces 处理。
+//
+// setns 只作用于调用线程，因此该函数会锁定当前 goroutine 所在线程且不再解锁，
+// 之后的 RunShell 必须在同一个 goroutine 中调用。goroutine 结束时运行时会直接销毁该线程。
+func JoinNamespaces(pid int, names []string) ([]JoinResult, error) {
+	runtime.LockOSThread()
+
+	// 先打开全部命名空间文件再逐个加入，避免加入 pid/mnt 后 /proc 视图发生变化
+	fds := make([]int, 0, len(names))
+	defer func() {
+		for _, fd := range fds {
+			unix.Close(fd)
+		}
+	}()
+
+	for _, name := range names {
+		path := fmt.Sprintf("/proc/%d/ns/%s", pid, name)
+		fd, err := unix.Open(path, unix.O_RDONLY|unix.O_CLOEXEC, 0)
+		if err != nil {
+			return nil, fmt.Errorf("打开命名空间 %s 失败: %w", path, err)
+		}
+		fds = append(fds, fd)
+	}
+
+	results := make([]JoinResult, 0, len(names))
+	for i, name := range names {
+		result := JoinResult{Name: name}
+
+		switch {
+		case sameNamespace(fds[i], name):
+			result.Reason = "与当前所在命名空间相同"
+		case name == "user":
+			// 内核禁止多线程进程通过 setns 切换 user 命名空间，而 Go 进程总是多线程的
+			result.Reason = "多线程进程无法通过 setns 加入 user 命名空间"
+		default:
+			if name == "mnt" {
+				// 线程间共享 fs_struct 时 setns(CLONE_NEWNS) 会返回 EINVAL，需要先取消共享
+				if err := unix.Unshare(unix.CLONE_FS); err != nil {
+					return results, fmt.Errorf("unshare(CLONE_FS) 失败: %w", err)
+				}
+			}
+			if err := unix.Setns(fds[i], namespaceFlags[name]); err != nil {
+				return results, fmt.Errorf("加入 %s 命名空间失败: %w", name, err)
+			}
+			result.Joined = true
+		}
+
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// RunShell 在已加入的命名空间中启动交互式 shell，返回 shell 的退出码
+func RunShell(opts ExecOptions) (int, error) {
+	// 子进程从当前（已锁定的）线程 fork，因此继承 JoinNamespaces 加入的命名空间；
+	// 若加入了 mnt 命名空间，shell 从容器的文件系统中查找
+	shell, args := interactiveShell()
+	cmd := exec.Command(shell, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "PS1="+opts.Prompt)
+
+	if opts.Verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] 启动 shell: %s %v\n", shell, args)
+	}
+
+	return runForeground(cmd)
+}
+
+// RunCommand 在已加入的命名空间中执行一次性命令，透传标准输入输出并返回命令的退出码
+func RunCommand(opts ExecOptions) (int, error) {
+	if len(opts.Command) == 0 {
+		return 0, fmt.Errorf("未指定要执行的命令")
+	}
+
+	// 与 RunShell 相同，命令路径在当前线程所在的命名空间中查找
+	cmd := exec.Command(opts.Command[0], opts.Command[1:]...)
+	if cmd.Err != nil {
+		return 0, fmt.Errorf("查找命令 %s 失败: %w", opts.Command[0], cmd.Err)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if opts.Verbose {
+		fmt.Fprintf(os.Stderr, "[DEBUG] 执行命令: %v\n", opts.Command)
+	}
+
+	return runForeground(cmd)
+}
+
+// sameNamespace 判断 fd 指向的命名空间是否与当前线程所在的相同
+func sameNamespace(fd int, name string) bool {
+	var target, current unix.Stat_t
+	if err := unix.Fstat(fd, &target); err != nil {
+		return false
+	}
+	if err := unix.Stat("/proc/thread-self/ns/"+name, &current); err != nil {
+		return false
+	}
+	return target.Dev == current.Dev && target.Ino == current.Ino
+}
+
+// interactiveShell 选择交互式 shell
+// bash 使用 --norc 以保证 PS1 不被 ~/.bashrc 覆盖
+func interactiveShell() (string, []string) {
+	if path, err := exec.LookPath("bash"); err == nil {
+		return path, []string{"--norc", "-i"}
+	}
+	return "/bin/sh", []string{"-i"}
+}
+
+// runForeground 在前台运行子进程并返回其退出码
+func runForeground(cmd *exec.Cmd) (int, error) {
+	// 子进程与当前进程处于同一前台进程组，Ctrl-C 等信号交由子进程自行处理
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGQUIT)
+	defer signal.Stop(sigCh)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+				return 128 + int(ws.Signal()), nil
+			}
+			return exitErr.ExitCode(), nil
+		}
+		return 0, fmt.Errorf("执行 %s 失败: %w", cmd.Path, err)
+	}
+	return 0, nil
+}
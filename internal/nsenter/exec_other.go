@@ -0,0 +1,18 @@
+//go:build !linux
+
+package nsenter
+
+// JoinNamespaces 非 Linux 平台不支持 setns
+func JoinNamespaces(pid int, names []string) ([]JoinResult, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// RunShell 非 Linux 平台不支持 setns
+func RunShell(opts ExecOptions) (int, error) {
+	return 0, ErrUnsupportedPlatform
+}
+
+// RunCommand 非 Linux 平台不支持 setns
+func RunCommand(opts ExecOptions) (int, error) {
+	return 0, ErrUnsupportedPlatform
+}
@@ -0,0 +1,66 @@
+package nsenter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AllNamespaces 支持加入的命名空间，顺序即加入顺序（与 nsenter 一致，mnt 最后加入）
+var AllNamespaces = []string{"user", "cgroup", "ipc", "uts", "net", "pid", "mnt"}
+
+// DefaultNamespaces 默认加入的命名空间（等价于 nsenter -n -p）
+var DefaultNamespaces = []string{"net", "pid"}
+
+// JoinResult 单个命名空间的加入结果
+type JoinResult struct {
+	Name   string // 命名空间类型，对应 /proc/<pid>/ns/<name>
+	Joined bool   // 是否实际加入
+	Reason string // 未加入的原因
+}
+
+// ParseNamespaces 校验命名空间列表，去重并按加入顺序排序
+func ParseNamespaces(names []string) ([]string, error) {
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !isKnownNamespace(name) {
+			return nil, fmt.Errorf("不支持的命名空间: %s (可选: %s)", name, strings.Join(AllNamespaces, ", "))
+		}
+		wanted[name] = true
+	}
+
+	if len(wanted) == 0 {
+		return nil, fmt.Errorf("至少需要指定一个命名空间")
+	}
+
+	var ordered []string
+	for _, name := range AllNamespaces {
+		if wanted[name] {
+			ordered = append(ordered, name)
+		}
+	}
+	return ordered, nil
+}
+
+// JoinedNames 返回实际加入的命名空间名称
+func JoinedNames(results []JoinResult) []string {
+	var names []string
+	for _, r := range results {
+		if r.Joined {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+func isKnownNamespace(name string) bool {
+	for _, n := range AllNamespaces {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package nsenter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNamespaces(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "default", input: DefaultNamespaces, want: []string{"net", "pid"}},
+		{name: "sorted into join order", input: []string{"mnt", "net", "user"}, want: []string{"user", "net", "mnt"}},
+		{name: "case and whitespace", input: []string{" NET ", "Pid"}, want: []string{"net", "pid"}},
+		{name: "duplicates", input: []string{"net", "net", "pid", "net"}, want: []string{"net", "pid"}},
+		{name: "empty entries skipped", input: []string{"", "uts", " "}, want: []string{"uts"}},
+		{name: "all", input: []string{"mnt", "pid", "net", "uts", "ipc", "cgroup", "user"}, want: AllNamespaces},
+		{name: "unknown", input: []string{"net", "time"}, wantErr: true},
+		{name: "nothing", input: []string{"", " "}, wantErr: true},
+		{name: "nil", input: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNamespaces(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNamespaces(%q) error = %v, wantErr %t", tt.input, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNamespaces(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinedNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []JoinResult
+		want    []string
+	}{
+		{name: "empty"},
+		{
+			name: "skips namespaces that were not joined",
+			results: []JoinResult{
+				{Name: "user", Joined: false, Reason: "already in the same namespace"},
+				{Name: "net", Joined: true},
+				{Name: "pid", Joined: true},
+				{Name: "mnt", Joined: false, Reason: "permission denied"},
+			},
+			want: []string{"net", "pid"},
+		},
+		{
+			name:    "none joined",
+			results: []JoinResult{{Name: "net"}, {Name: "pid"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JoinedNames(tt.results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("JoinedNames = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
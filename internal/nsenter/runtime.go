@@ -0,0 +1,171 @@
+package nsenter
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/trynocoding/k8s-toolkit/internal/kube"
+)
+
+const (
+	defaultContainerdSocket    = "/run/containerd/containerd.sock"
+	defaultContainerdNamespace = "k8s.io"
+
+	// 探测运行时是否可用的超时时间
+	probeTimeout = 3 * time.Second
+)
+
+// PIDResolver 根据容器 ID 获取容器主进程在宿主机上的 PID
+type PIDResolver interface {
+	Name() string
+	ContainerPID(ctx context.Context, containerID string) (int, error)
+	Close() error
+}
+
+// NewPIDResolver 创建 PID 解析器
+// Runtime 为 auto 时根据容器 ID 前缀决定探测顺序，未知前缀时依次探测 containerd、cri 和 docker
+func NewPIDResolver(ctx context.Context, opts ResolverOptions) (PIDResolver, error) {
+	switch opts.Runtime {
+	case "containerd", "docker", "cri":
+		return newResolver(ctx, opts.Runtime, opts)
+	case "auto", "":
+	default:
+		return nil, fmt.Errorf("不支持的容器运行时: %s (可选: auto, containerd, docker, cri)", opts.Runtime)
+	}
+
+	var errs []string
+	for _, name := range autoCandidates(opts.Hint) {
+		r, err := newResolver(ctx, name, opts)
+		if err == nil {
+			return r, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return nil, fmt.Errorf("%w: 无法检测到支持的容器运行时 (%s)", ErrRuntimeUnavailable, strings.Join(errs, "; "))
+}
+
+// autoCandidates 根据容器 ID 前缀返回 auto 模式下的探测顺序
+// containerd 使用非默认 socket 时可以回退到 CRI 接口
+func autoCandidates(hint string) []string {
+	switch hint {
+	case "containerd":
+		return []string{"containerd", "cri"}
+	case "docker":
+		return []string{"docker", "cri"}
+	case "cri-o":
+		return []string{"cri"}
+	default:
+		return []string{"containerd", "cri", "docker"}
+	}
+}
+
+func newResolver(ctx context.Context, name string, opts ResolverOptions) (PIDResolver, error) {
+	switch name {
+	case "containerd":
+		return newContainerdResolver(ctx)
+	case "docker":
+		return newDockerResolver(ctx)
+	default:
+		return newCRIResolver(ctx, opts.CRISocket)
+	}
+}
+
+// containerdResolver 通过 containerd 客户端获取任务 PID
+type containerdResolver struct {
+	client *containerd.Client
+}
+
+func newContainerdResolver(ctx context.Context) (*containerdResolver, error) {
+	if _, err := os.Stat(defaultContainerdSocket); err != nil {
+		return nil, fmt.Errorf("%w: containerd socket 不存在: %s", ErrRuntimeUnavailable, defaultContainerdSocket)
+	}
+
+	client, err := containerd.New(defaultContainerdSocket)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 连接 containerd 失败: %v", ErrRuntimeUnavailable, err)
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	if serving, err := client.IsServing(probeCtx); err != nil || !serving {
+		client.Close()
+		return nil, fmt.Errorf("%w: containerd 未就绪", ErrRuntimeUnavailable)
+	}
+
+	return &containerdResolver{client: client}, nil
+}
+
+func (r *containerdResolver) Name() string {
+	return "containerd"
+}
+
+func (r *containerdResolver) ContainerPID(ctx context.Context, containerID string) (int, error) {
+	ctx = namespaces.WithNamespace(ctx, defaultContainerdNamespace)
+
+	container, err := r.client.LoadContainer(ctx, containerID)
+	if err != nil {
+		return 0, fmt.Errorf("在 containerd 中找不到容器 '%s': %w", containerID, err)
+	}
+
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("获取容器 '%s' 的任务失败: %w", containerID, err)
+	}
+
+	pid := int(task.Pid())
+	if pid == 0 {
+		return 0, fmt.Errorf("%w: 容器 '%s' 的任务 PID 无效", kube.ErrContainerNotRunning, containerID)
+	}
+	return pid, nil
+}
+
+func (r *containerdResolver) Close() error {
+	return r.client.Close()
+}
+
+// dockerResolver 通过 Docker API 获取容器 PID
+type dockerResolver struct {
+	cli *dockerclient.Client
+}
+
+func newDockerResolver(ctx context.Context) (*dockerResolver, error) {
+	cli, err := dockerclient.NewClientWithOpts(dockerclient.FromEnv, dockerclient.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("%w: 创建 Docker 客户端失败: %v", ErrRuntimeUnavailable, err)
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	if _, err := cli.Ping(probeCtx); err != nil {
+		cli.Close()
+		return nil, fmt.Errorf("%w: docker 未就绪: %v", ErrRuntimeUnavailable, err)
+	}
+
+	return &dockerResolver{cli: cli}, nil
+}
+
+func (r *dockerResolver) Name() string {
+	return "docker"
+}
+
+func (r *dockerResolver) ContainerPID(ctx context.Context, containerID string) (int, error) {
+	info, err := r.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return 0, fmt.Errorf("无法获取容器 '%s' 的信息: %w", containerID, err)
+	}
+
+	if info.State == nil || !info.State.Running || info.State.Pid == 0 {
+		return 0, fmt.Errorf("%w: 容器 '%s' 的 PID 无效", kube.ErrContainerNotRunning, containerID)
+	}
+	return info.State.Pid, nil
+}
+
+func (r *dockerResolver) Close() error {
+	return r.cli.Close()
+}
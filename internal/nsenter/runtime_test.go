@@ -0,0 +1,27 @@
+package nsenter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAutoCandidates(t *testing.T) {
+	tests := []struct {
+		hint string
+		want []string
+	}{
+		{hint: "containerd", want: []string{"containerd", "cri"}},
+		{hint: "docker", want: []string{"docker", "cri"}},
+		{hint: "cri-o", want: []string{"cri"}},
+		{hint: "", want: []string{"containerd", "cri", "docker"}},
+		{hint: "unknown", want: []string{"containerd", "cri", "docker"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hint, func(t *testing.T) {
+			if got := autoCandidates(tt.hint); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("autoCandidates(%q) = %v, want %v", tt.hint, got, tt.want)
+			}
+		})
+	}
+}
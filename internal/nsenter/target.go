@@ -0,0 +1,27 @@
+package nsenter
+
+import (
+	"fmt"
+
+	"github.com/trynocoding/k8s-toolkit/internal/kube"
+)
+
+// NewContainerTarget 由已解析的容器生成进入目标，要求容器处于运行状态
+func NewContainerTarget(c *kube.Container) (*ContainerTarget, error) {
+	if err := c.CheckRunning(); err != nil {
+		return nil, err
+	}
+
+	runtimeHint, id := c.ContainerID()
+	if id == "" {
+		return nil, fmt.Errorf("%w: 无法获取容器 '%s' 的容器 ID", kube.ErrContainerNotFound, c.Name)
+	}
+
+	return &ContainerTarget{
+		PodName:     c.Pod.Name,
+		Namespace:   c.Pod.Namespace,
+		Name:        c.Name,
+		ID:          id,
+		RuntimeHint: runtimeHint,
+	}, nil
+}
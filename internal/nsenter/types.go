@@ -0,0 +1,35 @@
+package nsenter
+
+import (
+	"errors"
+)
+
+// 常见错误，调用方可以使用 errors.Is 判断
+// Pod 与容器相关的错误定义在 kube 包中
+var (
+	ErrRuntimeUnavailable  = errors.New("容器运行时不可用")
+	ErrUnsupportedPlatform = errors.New("当前平台不支持进入命名空间")
+)
+
+// ContainerTarget 表示已解析的目标容器
+type ContainerTarget struct {
+	PodName     string // Pod 名称
+	Namespace   string // Kubernetes 命名空间
+	Name        string // 容器名称
+	ID          string // 容器 ID（已去掉运行时前缀）
+	RuntimeHint string // 容器 ID 前缀中的运行时名称（如 containerd、docker）
+}
+
+// ResolverOptions 创建 PID 解析器的选项
+type ResolverOptions struct {
+	Runtime   string // auto|containerd|docker|cri
+	Hint      string // 容器 ID 前缀中的运行时名称，仅在 auto 模式下使用
+	CRISocket string // CRI socket 路径，为空时探测 DefaultCRISockets
+}
+
+// ExecOptions 在目标命名空间中启动进程的选项
+type ExecOptions struct {
+	Command []string // 一次性执行的命令及参数（为空时启动交互式 shell）
+	Prompt  string   // 交互式 shell 的 PS1 提示符
+	Verbose bool     // 详细输出
+}
@@ -0,0 +1,184 @@
+package procinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 容器运行时的默认 capability 集合
+var (
+	// DockerDefaultCapabilities containerd 和 docker 的默认集合 (0x00000000a80425fb)
+	DockerDefaultCapabilities = []string{
+		"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_FSETID", "CAP_FOWNER", "CAP_MKNOD",
+		"CAP_NET_RAW", "CAP_SETGID", "CAP_SETUID", "CAP_SETFCAP", "CAP_SETPCAP",
+		"CAP_NET_BIND_SERVICE", "CAP_SYS_CHROOT", "CAP_KILL", "CAP_AUDIT_WRITE",
+	}
+
+	// CRIODefaultCapabilities CRI-O 1.18 之后的默认集合
+	CRIODefaultCapabilities = []string{
+		"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_FSETID", "CAP_FOWNER",
+		"CAP_SETGID", "CAP_SETUID", "CAP_SETPCAP", "CAP_NET_BIND_SERVICE", "CAP_KILL",
+	}
+)
+
+// RuntimeDefaultCapabilities 返回容器运行时的默认 capability mask
+func RuntimeDefaultCapabilities(runtime string) (uint64, error) {
+	switch runtime {
+	case "containerd", "docker":
+		return EncodeCapabilityMask(DockerDefaultCapabilities)
+	case "cri-o", "crio":
+		return EncodeCapabilityMask(CRIODefaultCapabilities)
+	default:
+		return 0, fmt.Errorf("unknown runtime default capability set: %s (valid: containerd, docker, cri-o)", runtime)
+	}
+}
+
+// CapabilitySpec 描述容器 securityContext 中与 capability 相关的配置
+type CapabilitySpec struct {
+	Base       uint64   // 运行时默认集合
+	Add        []string // securityContext.capabilities.add
+	Drop       []string // securityContext.capabilities.drop
+	Privileged bool     // securityContext.privileged
+	LastCap    int      // 目标内核的 cap_last_cap，为 0 时使用本工具已知的全部 capability
+}
+
+// ExpectedCapabilities 按 containerd CRI 插件的规则计算容器应有的 bounding 集合:
+// privileged 时为全部 capability；否则依次应用 add ALL、drop ALL、add 和 drop
+// 全部 capability 以及最终结果都限制在目标内核支持的范围内 (0 到 LastCap)
+func ExpectedCapabilities(spec CapabilitySpec) (uint64, error) {
+	all, limit := knownCapabilityMask(), ^uint64(0)
+	if spec.LastCap > 0 {
+		all = capabilityMaskUpTo(spec.LastCap)
+		limit = all
+	}
+	if spec.Privileged {
+		return all, nil
+	}
+
+	caps := spec.Base
+	if containsAll(spec.Add) {
+		caps = all
+	}
+	if containsAll(spec.Drop) {
+		caps = 0
+	}
+	for _, name := range spec.Add {
+		if strings.EqualFold(name, "ALL") {
+			continue
+		}
+		bit, err := CapabilityBit(name)
+		if err != nil {
+			return 0, fmt.Errorf("securityContext.capabilities.add: %w", err)
+		}
+		caps |= 1 << uint(bit)
+	}
+	for _, name := range spec.Drop {
+		if strings.EqualFold(name, "ALL") {
+			continue
+		}
+		bit, err := CapabilityBit(name)
+		if err != nil {
+			return 0, fmt.Errorf("securityContext.capabilities.drop: %w", err)
+		}
+		caps &^= 1 << uint(bit)
+	}
+	return caps & limit, nil
+}
+
+// AuditCheck 一个 capability 集合的比较结果
+type AuditCheck struct {
+	Set      string   `json:"set"` // CapBnd 或 CapEff
+	Expected string   `json:"expected"`
+	Observed string   `json:"observed"`
+	Extra    []string `json:"extra"`   // 实际存在但配置中没有的
+	Missing  []string `json:"missing"` // 配置中有但实际不存在的
+	Note     string   `json:"note,omitempty"`
+}
+
+// AuditResult 进程 capability 与配置的比较结果
+type AuditResult struct {
+	PID    int          `json:"pid"`
+	Name   string       `json:"name"`
+	Checks []AuditCheck `json:"checks"`
+}
+
+// OK 判断是否没有任何差异
+func (r *AuditResult) OK() bool {
+	for _, c := range r.Checks {
+		if len(c.Extra) > 0 || len(c.Missing) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AuditCapabilities 比较进程实际的 CapBnd/CapEff 与期望的集合
+// CapBnd 应与期望完全一致；有效 UID 为 0 的进程 CapEff 也应一致，
+// 非 root 进程通常没有有效 capability，只报告多出的部分 (来自 ambient 或文件 capability)
+func AuditCapabilities(status *ProcessStatus, expected uint64) (*AuditResult, error) {
+	if status.Capabilities == nil {
+		return nil, fmt.Errorf("capabilities of process %d were not parsed", status.PID)
+	}
+	caps := status.Capabilities
+
+	result := &AuditResult{PID: status.PID, Name: status.Name}
+	result.Checks = append(result.Checks, compareCapabilities("CapBnd", expected, caps.Bounding, true))
+
+	eff := compareCapabilities("CapEff", expected, caps.Effective, status.UID.Effective == 0)
+	if status.UID.Effective != 0 {
+		eff.Note = fmt.Sprintf("non-root process (uid %d), only extra capabilities are reported", status.UID.Effective)
+	}
+	result.Checks = append(result.Checks, eff)
+
+	return result, nil
+}
+
+// compareCapabilities 比较两个 mask，reportMissing 为 false 时不报告缺少的部分
+func compareCapabilities(set string, expected, observed uint64, reportMissing bool) AuditCheck {
+	check := AuditCheck{
+		Set:      set,
+		Expected: fmt.Sprintf("0x%016x", expected),
+		Observed: fmt.Sprintf("0x%016x", observed),
+		Extra:    nonNil(DecodeCapabilityMask(observed &^ expected)),
+		Missing:  []string{},
+	}
+	if reportMissing {
+		check.Missing = nonNil(DecodeCapabilityMask(expected &^ observed))
+	}
+	return check
+}
+
+// FormatAuditResult 格式化审计结果
+func FormatAuditResult(r *AuditResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Process: %d (%s)\n", r.PID, r.Name))
+	for _, c := range r.Checks {
+		sb.WriteString(fmt.Sprintf("\n========== %s ==========\n", c.Set))
+		sb.WriteString(fmt.Sprintf("Expected: %s\n", c.Expected))
+		sb.WriteString(fmt.Sprintf("Observed: %s\n", c.Observed))
+		if c.Note != "" {
+			sb.WriteString(fmt.Sprintf("Note:     %s\n", c.Note))
+		}
+		if len(c.Extra) == 0 && len(c.Missing) == 0 {
+			sb.WriteString("Result:   OK\n")
+			continue
+		}
+		if len(c.Extra) > 0 {
+			sb.WriteString(fmt.Sprintf("Extra:    %s\n", strings.Join(c.Extra, ", ")))
+		}
+		if len(c.Missing) > 0 {
+			sb.WriteString(fmt.Sprintf("Missing:  %s\n", strings.Join(c.Missing, ", ")))
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// containsAll 判断列表中是否有 ALL
+func containsAll(names []string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, "ALL") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package procinfo
+
+import "testing"
+
+func TestExpectedCapabilities(t *testing.T) {
+	dockerDefault, err := RuntimeDefaultCapabilities("containerd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	crioDefault, err := RuntimeDefaultCapabilities("cri-o")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		spec    CapabilitySpec
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name: "runtime default",
+			spec: CapabilitySpec{Base: dockerDefault, LastCap: 40},
+			want: 0xa80425fb,
+		},
+		{
+			name: "cri-o default",
+			spec: CapabilitySpec{Base: crioDefault, LastCap: 40},
+			want: crioDefault,
+		},
+		{
+			name: "add a capability",
+			spec: CapabilitySpec{Base: dockerDefault, Add: []string{"NET_ADMIN"}, LastCap: 40},
+			want: 0xa80425fb | 1<<12,
+		},
+		{
+			name: "drop a capability",
+			spec: CapabilitySpec{Base: dockerDefault, Drop: []string{"CAP_NET_RAW"}, LastCap: 40},
+			want: 0xa80425fb &^ (1 << 13),
+		},
+		{
+			name: "drop ALL",
+			spec: CapabilitySpec{Base: dockerDefault, Drop: []string{"ALL"}, LastCap: 40},
+			want: 0,
+		},
+		{
+			name: "drop ALL then add one",
+			spec: CapabilitySpec{Base: dockerDefault, Drop: []string{"ALL"}, Add: []string{"NET_BIND_SERVICE"}, LastCap: 40},
+			want: 1 << 10,
+		},
+		{
+			name: "add ALL",
+			spec: CapabilitySpec{Base: dockerDefault, Add: []string{"ALL"}, LastCap: 40},
+			want: 1<<41 - 1,
+		},
+		{
+			name: "privileged",
+			spec: CapabilitySpec{Base: dockerDefault, Privileged: true, Drop: []string{"ALL"}, LastCap: 40},
+			want: 1<<41 - 1,
+		},
+		{
+			name: "privileged on an older kernel",
+			spec: CapabilitySpec{Base: dockerDefault, Privileged: true, LastCap: 37},
+			want: 1<<38 - 1,
+		},
+		{
+			name: "privileged on a newer kernel",
+			spec: CapabilitySpec{Base: dockerDefault, Privileged: true, LastCap: 42},
+			want: 1<<43 - 1,
+		},
+		{
+			name: "add beyond cap_last_cap",
+			spec: CapabilitySpec{Base: dockerDefault, Add: []string{"CAP_CHECKPOINT_RESTORE"}, LastCap: 37},
+			want: 0xa80425fb,
+		},
+		{
+			name: "privileged without cap_last_cap",
+			spec: CapabilitySpec{Privileged: true},
+			want: knownCapabilityMask(),
+		},
+		{
+			name:    "unknown capability",
+			spec:    CapabilitySpec{Base: dockerDefault, Add: []string{"CAP_FLY"}, LastCap: 40},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpectedCapabilities(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpectedCapabilities error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ExpectedCapabilities = %#x (%s), want %#x (%s)",
+					got, FormatCapabilityMask(got), tt.want, FormatCapabilityMask(tt.want))
+			}
+		})
+	}
+}
+
+func TestParseLastCap(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{input: "40\n", want: 40},
+		{input: " 37 ", want: 37},
+		{input: "63", want: 63},
+		{input: "64", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLastCap(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLastCap(%q) = %d, %v; want %d, wantErr %t", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package procinfo
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -62,7 +63,7 @@ func DecodeCapabilityMask(mask uint64) []string {
 			if name, ok := capabilityNames[bit]; ok {
 				caps = append(caps, name)
 			} else {
-				caps = append(caps, fmt.Sprintf("CAP_UNKNOWN_%d", bit))
+				caps = append(caps, unknownCapabilityName(bit))
 			}
 		}
 	}
@@ -91,5 +92,103 @@ func FormatCapabilitiesInfo(caps *CapabilitiesInfo) string {
 	sb.WriteString(fmt.Sprintf("CapEff: 0x%016x -> %s\n", caps.Effective, FormatCapabilityMask(caps.Effective)))
 	sb.WriteString(fmt.Sprintf("CapBnd: 0x%016x -> %s\n", caps.Bounding, FormatCapabilityMask(caps.Bounding)))
 	sb.WriteString(fmt.Sprintf("CapAmb: 0x%016x -> %s", caps.Ambient, FormatCapabilityMask(caps.Ambient)))
+
+	// 内核比本工具新时会出现未知的 capability 位
+	all := caps.Inheritable | caps.Permitted | caps.Effective | caps.Bounding | caps.Ambient
+	if unknown := UnknownCapabilityBits(all); len(unknown) > 0 {
+		sb.WriteString(fmt.Sprintf("\nNote:   bits %s are unknown to this build (kernel newer than CAP_%s, shown as CAP_UNKNOWN_<bit>)",
+			formatIntList(unknown), strings.TrimPrefix(capabilityNames[lastKnownCapability()], "CAP_")))
+	}
 	return sb.String()
 }
+
+// UnknownCapabilityBits 返回 mask 中 capabilityNames 未收录的位
+func UnknownCapabilityBits(mask uint64) []int {
+	var bits []int
+	for bit := 0; bit < 64; bit++ {
+		if mask&(1<<uint(bit)) != 0 {
+			if _, ok := capabilityNames[bit]; !ok {
+				bits = append(bits, bit)
+			}
+		}
+	}
+	return bits
+}
+
+// knownCapabilityMask 返回所有已知 capability 组成的 mask
+func knownCapabilityMask() uint64 {
+	var mask uint64
+	for bit := range capabilityNames {
+		mask |= 1 << uint(bit)
+	}
+	return mask
+}
+
+// CapLastCapPath 内核支持的最大 capability 位号
+const CapLastCapPath = "/proc/sys/kernel/cap_last_cap"
+
+// ParseLastCap 解析 cap_last_cap 的内容
+func ParseLastCap(s string) (int, error) {
+	last, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || last < 0 || last > 63 {
+		return 0, fmt.Errorf("invalid cap_last_cap: %q", strings.TrimSpace(s))
+	}
+	return last, nil
+}
+
+// capabilityMaskUpTo 返回 0 到 last 位全部置位的 mask
+func capabilityMaskUpTo(last int) uint64 {
+	if last >= 63 {
+		return ^uint64(0)
+	}
+	return 1<<uint(last+1) - 1
+}
+
+// lastKnownCapability 返回已知的最大 capability 位号 (对应内核的 CAP_LAST_CAP)
+func lastKnownCapability() int {
+	last := 0
+	for bit := range capabilityNames {
+		if bit > last {
+			last = bit
+		}
+	}
+	return last
+}
+
+// unknownCapabilityName 返回未知 capability 位的名称
+func unknownCapabilityName(bit int) string {
+	return fmt.Sprintf("CAP_UNKNOWN_%d", bit)
+}
+
+// CapabilityBit 根据名称查找 capability 的位号
+// 名称不区分大小写，可以省略 CAP_ 前缀；也接受 DecodeCapabilityMask 输出的 CAP_UNKNOWN_<bit>
+func CapabilityBit(name string) (int, error) {
+	upper := strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(upper, "CAP_") {
+		upper = "CAP_" + upper
+	}
+	for bit, n := range capabilityNames {
+		if n == upper {
+			return bit, nil
+		}
+	}
+	if rest, ok := strings.CutPrefix(upper, "CAP_UNKNOWN_"); ok {
+		if bit, err := strconv.Atoi(rest); err == nil && bit >= 0 && bit < 64 {
+			return bit, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown capability: %s", name)
+}
+
+// EncodeCapabilityMask 将 capability 名称列表编码为 bitmask，名称规则同 CapabilityBit
+func EncodeCapabilityMask(names []string) (uint64, error) {
+	var mask uint64
+	for _, name := range names {
+		bit, err := CapabilityBit(name)
+		if err != nil {
+			return 0, err
+		}
+		mask |= 1 << uint(bit)
+	}
+	return mask, nil
+}
@@ -0,0 +1,160 @@
+package procinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetDiff 一个 capability 或信号集合的差异
+type SetDiff struct {
+	Set     string   `json:"set"` // 如 CapEff、SigCgt
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// StatusDiff 两个进程状态之间的差异，Added/Removed 均以 Left 为基准
+type StatusDiff struct {
+	Left         string    `json:"left"`
+	Right        string    `json:"right"`
+	Capabilities []SetDiff `json:"capabilities"`
+	Signals      []SetDiff `json:"signals"`
+}
+
+// Empty 判断是否没有任何差异
+func (d *StatusDiff) Empty() bool {
+	return len(d.Capabilities) == 0 && len(d.Signals) == 0
+}
+
+// DiffCapabilities 比较两组 capability，只返回有差异的集合
+// 任意一侧没有 capability 部分 (如快照只保存了 signals) 时返回错误，而不是报告没有差异
+func DiffCapabilities(a, b *CapabilitiesInfo) ([]SetDiff, error) {
+	if err := checkSections("capabilities", a == nil, b == nil, "left", "right"); err != nil {
+		return nil, err
+	}
+	sets := []struct {
+		name string
+		a, b uint64
+	}{
+		{"CapInh", a.Inheritable, b.Inheritable},
+		{"CapPrm", a.Permitted, b.Permitted},
+		{"CapEff", a.Effective, b.Effective},
+		{"CapBnd", a.Bounding, b.Bounding},
+		{"CapAmb", a.Ambient, b.Ambient},
+	}
+
+	var diffs []SetDiff
+	for _, s := range sets {
+		if s.a == s.b {
+			continue
+		}
+		diffs = append(diffs, SetDiff{
+			Set:     s.name,
+			Added:   nonNil(DecodeCapabilityMask(s.b &^ s.a)),
+			Removed: nonNil(DecodeCapabilityMask(s.a &^ s.b)),
+		})
+	}
+	return diffs, nil
+}
+
+// DiffSignals 比较两组信号集合，只返回有差异的集合
+// 任意一侧没有 signals 部分时返回错误
+func DiffSignals(a, b *SignalsInfo) ([]SetDiff, error) {
+	if err := checkSections("signals", a == nil, b == nil, "left", "right"); err != nil {
+		return nil, err
+	}
+	sets := []struct {
+		name string
+		a, b uint64
+	}{
+		{"SigPnd", a.Pending, b.Pending},
+		{"ShdPnd", a.SharedPending, b.SharedPending},
+		{"SigBlk", a.Blocked, b.Blocked},
+		{"SigIgn", a.Ignored, b.Ignored},
+		{"SigCgt", a.Caught, b.Caught},
+	}
+
+	var diffs []SetDiff
+	for _, s := range sets {
+		if s.a == s.b {
+			continue
+		}
+		diffs = append(diffs, SetDiff{
+			Set:     s.name,
+			Added:   nonNil(DecodeSignalMask(s.b &^ s.a)),
+			Removed: nonNil(DecodeSignalMask(s.a &^ s.b)),
+		})
+	}
+	return diffs, nil
+}
+
+// DiffStatus 比较两个进程的 capability 和信号状态
+// 缺少某个部分时错误信息中使用 leftLabel/rightLabel 指明是哪个进程或快照
+func DiffStatus(left, right *ProcessStatus, leftLabel, rightLabel string) (*StatusDiff, error) {
+	if err := checkSections("capabilities", left.Capabilities == nil, right.Capabilities == nil, leftLabel, rightLabel); err != nil {
+		return nil, err
+	}
+	if err := checkSections("signals", left.Signals == nil, right.Signals == nil, leftLabel, rightLabel); err != nil {
+		return nil, err
+	}
+
+	caps, err := DiffCapabilities(left.Capabilities, right.Capabilities)
+	if err != nil {
+		return nil, err
+	}
+	signals, err := DiffSignals(left.Signals, right.Signals)
+	if err != nil {
+		return nil, err
+	}
+	return &StatusDiff{
+		Left:         leftLabel,
+		Right:        rightLabel,
+		Capabilities: nonNil(caps),
+		Signals:      nonNil(signals),
+	}, nil
+}
+
+// checkSections 检查两侧是否都有指定的部分，错误信息中以 label 指明缺少的一侧
+func checkSections(section string, leftMissing, rightMissing bool, leftLabel, rightLabel string) error {
+	switch {
+	case leftMissing:
+		return fmt.Errorf("%s has no %s section", leftLabel, section)
+	case rightMissing:
+		return fmt.Errorf("%s has no %s section", rightLabel, section)
+	}
+	return nil
+}
+
+// FormatStatusDiff 格式化差异，+ 表示只在右侧存在，- 表示只在左侧存在
+func FormatStatusDiff(d *StatusDiff) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- %s\n", d.Left))
+	sb.WriteString(fmt.Sprintf("+++ %s\n", d.Right))
+
+	if d.Empty() {
+		sb.WriteString("\nNo differences")
+		return sb.String()
+	}
+
+	for _, group := range []struct {
+		title string
+		diffs []SetDiff
+	}{
+		{"Capabilities", d.Capabilities},
+		{"Signals", d.Signals},
+	} {
+		if len(group.diffs) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n========== %s ==========\n", group.title))
+		for _, sd := range group.diffs {
+			sb.WriteString(fmt.Sprintf("%s:\n", sd.Set))
+			for _, name := range sd.Added {
+				sb.WriteString(fmt.Sprintf("  + %s\n", name))
+			}
+			for _, name := range sd.Removed {
+				sb.WriteString(fmt.Sprintf("  - %s\n", name))
+			}
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,132 @@
+package procinfo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+// roundTrip 将状态序列化为 proc-status -o json|yaml 的输出后再读回，与 proc-status diff 读取快照的方式一致
+func roundTrip(t *testing.T, status *ProcessStatus, format string) *ProcessStatus {
+	t.Helper()
+	var data []byte
+	var err error
+	if format == "json" {
+		data, err = json.Marshal(ToDocument(status))
+	} else {
+		data, err = yaml.Marshal(ToDocument(status))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc StatusDocument
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal %s snapshot: %v", format, err)
+	}
+	got, err := FromDocument(&doc)
+	if err != nil {
+		t.Fatalf("FromDocument: %v", err)
+	}
+	return got
+}
+
+func TestDocumentRoundTrip(t *testing.T) {
+	want, err := ParseStatus(openFixture(t, "normal.status"), allOptions(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			if got := roundTrip(t, want, format); !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip mismatch:\n got: %+v\nwant: %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestFromDocumentInvalidMask(t *testing.T) {
+	doc := ToDocument(&ProcessStatus{PID: 1, Capabilities: &CapabilitiesInfo{}})
+	doc.Capabilities.Effective.Mask = "0xzz"
+	if _, err := FromDocument(doc); err == nil || !strings.Contains(err.Error(), "capabilities.effective") {
+		t.Errorf("FromDocument error = %v, want an invalid capabilities.effective mask", err)
+	}
+}
+
+func TestDiffStatus(t *testing.T) {
+	base, err := ParseStatus(openFixture(t, "normal.status"), ParseOptions{ParseCapabilities: true, ParseSignals: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 快照只保存了 signals 部分
+	signalsOnly, err := ParseStatus(openFixture(t, "normal.status"), ParseOptions{ParseSignals: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed := *base
+	caps := *base.Capabilities
+	caps.Effective = caps.Effective&^(1<<13) | 1<<12 // - CAP_NET_RAW, + CAP_NET_ADMIN
+	changed.Capabilities = &caps
+
+	tests := []struct {
+		name     string
+		left     *ProcessStatus
+		right    *ProcessStatus
+		wantCaps []SetDiff
+		wantErr  string
+	}{
+		{
+			name:     "identical",
+			left:     base,
+			right:    base,
+			wantCaps: []SetDiff{},
+		},
+		{
+			name:  "added and removed capability",
+			left:  base,
+			right: &changed,
+			wantCaps: []SetDiff{
+				{Set: "CapEff", Added: []string{"CAP_NET_ADMIN"}, Removed: []string{"CAP_NET_RAW"}},
+			},
+		},
+		{
+			name:    "right snapshot without capabilities",
+			left:    base,
+			right:   roundTrip(t, signalsOnly, "json"),
+			wantErr: "after.json: pid 1234 (nginx) has no capabilities section",
+		},
+		{
+			name:    "left snapshot without capabilities",
+			left:    roundTrip(t, signalsOnly, "yaml"),
+			right:   base,
+			wantErr: "before.json: pid 1234 (nginx) has no capabilities section",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leftLabel := "before.json: pid 1234 (nginx)"
+			rightLabel := "after.json: pid 1234 (nginx)"
+			diff, err := DiffStatus(tt.left, tt.right, leftLabel, rightLabel)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("DiffStatus error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DiffStatus: %v", err)
+			}
+			if !reflect.DeepEqual(diff.Capabilities, tt.wantCaps) {
+				t.Errorf("capability diff = %+v, want %+v", diff.Capabilities, tt.wantCaps)
+			}
+			if len(diff.Signals) != 0 {
+				t.Errorf("signal diff = %+v, want none", diff.Signals)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package procinfo
+
+import (
+	"fmt"
+)
+
+// StatusDocument 是 ProcessStatus 的 JSON/YAML 表示
+// 字段名和结构是对外的稳定格式，每个 mask 同时给出原始值和解码后的名称
+type StatusDocument struct {
+	PID          int                   `json:"pid"`
+	Name         string                `json:"name"`
+	State        string                `json:"state"`
+	PPid         int                   `json:"ppid"`
+	Threads      int                   `json:"threads"`
+	NSpid        []int                 `json:"nspid"`
+	NStgid       []int                 `json:"nstgid"`
+	UID          IDs                   `json:"uid"`
+	GID          IDs                   `json:"gid"`
+	Groups       []int                 `json:"groups"`
+	Capabilities *CapabilitiesDocument `json:"capabilities,omitempty"`
+	Signals      *SignalsDocument      `json:"signals,omitempty"`
+	Memory       *MemoryDocument       `json:"memory,omitempty"`
+	Security     *SecurityDocument     `json:"security,omitempty"`
+	Scheduling   *SchedulingDocument   `json:"scheduling,omitempty"`
+}
+
+// MemoryDocument 表示内存使用，单位 kB
+type MemoryDocument struct {
+	VmRSSKB uint64 `json:"vmRSSKB"`
+	VmHWMKB uint64 `json:"vmHWMKB"`
+}
+
+// SecurityDocument 表示 seccomp 和 no_new_privs 状态
+type SecurityDocument struct {
+	Seccomp        string `json:"seccomp"` // disabled, strict, filter
+	SeccompFilters int    `json:"seccompFilters"`
+	NoNewPrivs     bool   `json:"noNewPrivs"`
+}
+
+// SchedulingDocument 表示 CPU 亲和性和上下文切换次数
+type SchedulingDocument struct {
+	CpusAllowedList          string `json:"cpusAllowedList"`
+	VoluntaryCtxtSwitches    uint64 `json:"voluntaryCtxtSwitches"`
+	NonvoluntaryCtxtSwitches uint64 `json:"nonvoluntaryCtxtSwitches"`
+}
+
+// MaskDocument 表示一个 bitmask：十六进制字符串形式的原始值和解码后的名称
+// 原始值使用字符串，避免 jq 等工具按 float64 处理时丢失精度
+type MaskDocument struct {
+	Mask  string   `json:"mask"`
+	Names []string `json:"names"`
+}
+
+// CapabilitiesDocument 表示五个 capability 集合
+type CapabilitiesDocument struct {
+	Inheritable MaskDocument `json:"inheritable"`
+	Permitted   MaskDocument `json:"permitted"`
+	Effective   MaskDocument `json:"effective"`
+	Bounding    MaskDocument `json:"bounding"`
+	Ambient     MaskDocument `json:"ambient"`
+}
+
+// SignalsDocument 表示信号队列和五个信号集合
+type SignalsDocument struct {
+	Queued        string       `json:"queued"`
+	Pending       MaskDocument `json:"pending"`
+	SharedPending MaskDocument `json:"sharedPending"`
+	Blocked       MaskDocument `json:"blocked"`
+	Ignored       MaskDocument `json:"ignored"`
+	Caught        MaskDocument `json:"caught"`
+}
+
+// ToDocument 将 ProcessStatus 转换为可序列化的文档
+func ToDocument(status *ProcessStatus) *StatusDocument {
+	doc := &StatusDocument{
+		PID:     status.PID,
+		Name:    status.Name,
+		State:   status.State,
+		PPid:    status.PPid,
+		Threads: status.Threads,
+		NSpid:   nonNil(status.NSpid),
+		NStgid:  nonNil(status.NStgid),
+		UID:     status.UID,
+		GID:     status.GID,
+		Groups:  nonNil(status.Groups),
+	}
+
+	if caps := status.Capabilities; caps != nil {
+		doc.Capabilities = &CapabilitiesDocument{
+			Inheritable: CapabilityMaskDocument(caps.Inheritable),
+			Permitted:   CapabilityMaskDocument(caps.Permitted),
+			Effective:   CapabilityMaskDocument(caps.Effective),
+			Bounding:    CapabilityMaskDocument(caps.Bounding),
+			Ambient:     CapabilityMaskDocument(caps.Ambient),
+		}
+	}
+
+	if sigs := status.Signals; sigs != nil {
+		doc.Signals = &SignalsDocument{
+			Queued:        sigs.Queued,
+			Pending:       SignalMaskDocument(sigs.Pending),
+			SharedPending: SignalMaskDocument(sigs.SharedPending),
+			Blocked:       SignalMaskDocument(sigs.Blocked),
+			Ignored:       SignalMaskDocument(sigs.Ignored),
+			Caught:        SignalMaskDocument(sigs.Caught),
+		}
+	}
+
+	if mem := status.Memory; mem != nil {
+		doc.Memory = &MemoryDocument{VmRSSKB: mem.VmRSS, VmHWMKB: mem.VmHWM}
+	}
+	if sec := status.Security; sec != nil {
+		doc.Security = &SecurityDocument{
+			Seccomp:        SeccompModeName(sec.Seccomp),
+			SeccompFilters: sec.SeccompFilters,
+			NoNewPrivs:     sec.NoNewPrivs,
+		}
+	}
+	if sched := status.Scheduling; sched != nil {
+		doc.Scheduling = &SchedulingDocument{
+			CpusAllowedList:          sched.CpusAllowedList,
+			VoluntaryCtxtSwitches:    sched.VoluntaryCtxtSwitches,
+			NonvoluntaryCtxtSwitches: sched.NonvoluntaryCtxtSwitches,
+		}
+	}
+
+	return doc
+}
+
+// FromDocument 将文档转换回 ProcessStatus，mask 以原始值为准，忽略名称列表
+func FromDocument(doc *StatusDocument) (*ProcessStatus, error) {
+	status := &ProcessStatus{
+		PID:     doc.PID,
+		Name:    doc.Name,
+		State:   doc.State,
+		PPid:    doc.PPid,
+		Threads: doc.Threads,
+		NSpid:   doc.NSpid,
+		NStgid:  doc.NStgid,
+		UID:     doc.UID,
+		GID:     doc.GID,
+		Groups:  doc.Groups,
+	}
+
+	var errs []error
+	mask := func(field string, m MaskDocument) uint64 {
+		v, err := parseHexUint64(m.Mask)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid mask %q", field, m.Mask))
+		}
+		return v
+	}
+
+	if caps := doc.Capabilities; caps != nil {
+		status.Capabilities = &CapabilitiesInfo{
+			Inheritable: mask("capabilities.inheritable", caps.Inheritable),
+			Permitted:   mask("capabilities.permitted", caps.Permitted),
+			Effective:   mask("capabilities.effective", caps.Effective),
+			Bounding:    mask("capabilities.bounding", caps.Bounding),
+			Ambient:     mask("capabilities.ambient", caps.Ambient),
+		}
+	}
+
+	if sigs := doc.Signals; sigs != nil {
+		status.Signals = &SignalsInfo{
+			Queued:        sigs.Queued,
+			Pending:       mask("signals.pending", sigs.Pending),
+			SharedPending: mask("signals.sharedPending", sigs.SharedPending),
+			Blocked:       mask("signals.blocked", sigs.Blocked),
+			Ignored:       mask("signals.ignored", sigs.Ignored),
+			Caught:        mask("signals.caught", sigs.Caught),
+		}
+	}
+
+	if mem := doc.Memory; mem != nil {
+		status.Memory = &MemoryInfo{VmRSS: mem.VmRSSKB, VmHWM: mem.VmHWMKB}
+	}
+	if sec := doc.Security; sec != nil {
+		status.Security = &SecurityInfo{
+			Seccomp:        seccompMode(sec.Seccomp),
+			SeccompFilters: sec.SeccompFilters,
+			NoNewPrivs:     sec.NoNewPrivs,
+		}
+	}
+	if sched := doc.Scheduling; sched != nil {
+		status.Scheduling = &SchedulingInfo{
+			CpusAllowedList:          sched.CpusAllowedList,
+			VoluntaryCtxtSwitches:    sched.VoluntaryCtxtSwitches,
+			NonvoluntaryCtxtSwitches: sched.NonvoluntaryCtxtSwitches,
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return status, nil
+}
+
+// seccompMode 是 SeccompModeName 的逆操作
+func seccompMode(name string) int {
+	for mode := 0; mode <= 2; mode++ {
+		if SeccompModeName(mode) == name {
+			return mode
+		}
+	}
+	return -1
+}
+
+// nonNil 保证空列表序列化为 [] 而不是 null
+func nonNil[T any](list []T) []T {
+	if list == nil {
+		return []T{}
+	}
+	return list
+}
+
+// CapabilityMaskDocument 创建 capability mask 的文档表示
+func CapabilityMaskDocument(mask uint64) MaskDocument {
+	return newMaskDocument(mask, DecodeCapabilityMask(mask))
+}
+
+// SignalMaskDocument 使用本机的信号表创建信号 mask 的文档表示
+func SignalMaskDocument(mask uint64) MaskDocument {
+	return hostSignals.MaskDocument(mask)
+}
+
+// newMaskDocument 创建 MaskDocument，空集合输出为 [] 而不是 null
+func newMaskDocument(mask uint64, names []string) MaskDocument {
+	return MaskDocument{
+		Mask:  fmt.Sprintf("0x%016x", mask),
+		Names: nonNil(names),
+	}
+}
+
+// ThreadsDocument 是线程分组的 JSON/YAML 表示
+type ThreadsDocument struct {
+	PID       int                   `json:"pid"`
+	Name      string                `json:"name"`
+	Threads   int                   `json:"threads"`
+	Groups    []ThreadGroupDocument `json:"groups"`
+	Recipient *SignalRecipient      `json:"recipient,omitempty"`
+}
+
+// ThreadGroupDocument 表示一组 mask 相同的线程
+type ThreadGroupDocument struct {
+	TIDs      []int         `json:"tids"`
+	Names     []string      `json:"names"`
+	Pending   MaskDocument  `json:"pending"`
+	Blocked   MaskDocument  `json:"blocked"`
+	Effective *MaskDocument `json:"effective,omitempty"`
+}
+
+// ThreadsToDocument 将线程分组转换为可序列化的文档
+func ThreadsToDocument(pid int, name string, threads []*ProcessStatus, groups []*ThreadGroup, recipient *SignalRecipient) *ThreadsDocument {
+	doc := &ThreadsDocument{
+		PID:       pid,
+		Name:      name,
+		Threads:   len(threads),
+		Groups:    make([]ThreadGroupDocument, 0, len(groups)),
+		Recipient: recipient,
+	}
+	for _, g := range groups {
+		gd := ThreadGroupDocument{
+			TIDs:    make([]int, 0, len(g.Threads)),
+			Names:   make([]string, 0, len(g.Threads)),
+			Pending: SignalMaskDocument(g.Pending),
+			Blocked: SignalMaskDocument(g.Blocked),
+		}
+		for _, t := range g.Threads {
+			gd.TIDs = append(gd.TIDs, t.PID)
+			gd.Names = append(gd.Names, t.Name)
+		}
+		if len(g.Threads) > 0 && g.Threads[0].Capabilities != nil {
+			eff := CapabilityMaskDocument(g.Effective)
+			gd.Effective = &eff
+		}
+		doc.Groups = append(doc.Groups, gd)
+	}
+	return doc
+}
+
+// TreeNodeDocument 是进程树节点的 JSON/YAML 表示
+type TreeNodeDocument struct {
+	PID        int                `json:"pid"`
+	Name       string             `json:"name"`
+	UID        IDs                `json:"uid"`
+	Effective  *MaskDocument      `json:"effective,omitempty"`
+	NoNewPrivs *bool              `json:"noNewPrivs,omitempty"`
+	Gained     MaskDocument       `json:"gained"`
+	BecameRoot bool               `json:"becameRoot"`
+	Escalated  bool               `json:"escalated"`
+	Children   []TreeNodeDocument `json:"children"`
+}
+
+// TreeToDocument 将进程树转换为可序列化的文档
+func TreeToDocument(roots []*ProcessNode) []TreeNodeDocument {
+	docs := make([]TreeNodeDocument, 0, len(roots))
+	for _, node := range roots {
+		status := node.Status
+		doc := TreeNodeDocument{
+			PID:        status.PID,
+			Name:       status.Name,
+			UID:        status.UID,
+			Gained:     CapabilityMaskDocument(node.Gained),
+			BecameRoot: node.BecameRoot,
+			Escalated:  node.Escalated(),
+			Children:   TreeToDocument(node.Children),
+		}
+		if status.Capabilities != nil {
+			eff := CapabilityMaskDocument(status.Capabilities.Effective)
+			doc.Effective = &eff
+		}
+		if status.Security != nil {
+			nnp := status.Security.NoNewPrivs
+			doc.NoNewPrivs = &nnp
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
@@ -3,6 +3,7 @@ package procinfo
 import (
 	"fmt"
 	"strings"
+	"text/tabwriter"
 )
 
 // FormatProcessStatus 格式化进程状态为可读字符串
@@ -14,22 +15,117 @@ func FormatProcessStatus(status *ProcessStatus, opts ParseOptions) string {
 	if status.State != "" {
 		sb.WriteString(fmt.Sprintf("State:   %s\n", status.State))
 	}
+	if opts.ParseIdentity {
+		sb.WriteString(formatIdentity(status))
+	}
 
-	// Capabilities 信息
-	if status.Capabilities != nil {
-		sb.WriteString("\n========== Capabilities ==========\n")
-		sb.WriteString(FormatCapabilitiesInfo(status.Capabilities))
+	sections := []struct {
+		title string
+		body  string
+	}{
+		{"Capabilities", FormatCapabilitiesInfo(status.Capabilities)},
+		{"Signals", FormatSignalsInfo(status.Signals)},
+		{"Memory", formatMemoryInfo(status.Memory)},
+		{"Security", formatSecurityInfo(status.Security)},
+		{"Scheduling", formatSchedulingInfo(status.Scheduling)},
+	}
+	for _, sec := range sections {
+		if sec.body == "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n========== %s ==========\n", sec.title))
+		sb.WriteString(sec.body)
+		sb.WriteString("\n")
 	}
 
-	// Signals 信息
-	if status.Signals != nil {
-		sb.WriteString("\n\n========== Signals ==========\n")
-		sb.WriteString(FormatSignalsInfo(status.Signals))
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// formatIdentity 格式化进程标识信息
+func formatIdentity(status *ProcessStatus) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("PPid:    %d\n", status.PPid))
+	sb.WriteString(fmt.Sprintf("Threads: %d\n", status.Threads))
+	if len(status.NSpid) > 0 {
+		sb.WriteString(fmt.Sprintf("NSpid:   %s", formatIntList(status.NSpid)))
+		if len(status.NSpid) > 1 {
+			sb.WriteString(fmt.Sprintf(" (PID in container: %d)", status.NSpid[len(status.NSpid)-1]))
+		}
+		sb.WriteString("\n")
+	}
+	if len(status.NStgid) > 0 {
+		sb.WriteString(fmt.Sprintf("NStgid:  %s\n", formatIntList(status.NStgid)))
 	}
+	sb.WriteString(fmt.Sprintf("Uid:     %s\n", formatIDs(status.UID)))
+	sb.WriteString(fmt.Sprintf("Gid:     %s\n", formatIDs(status.GID)))
+	groups := formatIntList(status.Groups)
+	if groups == "" {
+		groups = "<none>"
+	}
+	sb.WriteString(fmt.Sprintf("Groups:  %s\n", groups))
+	return sb.String()
+}
 
+// formatMemoryInfo 格式化内存信息
+func formatMemoryInfo(mem *MemoryInfo) string {
+	if mem == nil {
+		return ""
+	}
+	return fmt.Sprintf("VmRSS: %d kB\nVmHWM: %d kB", mem.VmRSS, mem.VmHWM)
+}
+
+// formatSecurityInfo 格式化 seccomp 和 no_new_privs 信息
+func formatSecurityInfo(sec *SecurityInfo) string {
+	if sec == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Seccomp:         %d (%s)\n", sec.Seccomp, SeccompModeName(sec.Seccomp)))
+	sb.WriteString(fmt.Sprintf("Seccomp_filters: %d\n", sec.SeccompFilters))
+	sb.WriteString(fmt.Sprintf("NoNewPrivs:      %t", sec.NoNewPrivs))
+	return sb.String()
+}
+
+// formatSchedulingInfo 格式化 CPU 亲和性和上下文切换信息
+func formatSchedulingInfo(sched *SchedulingInfo) string {
+	if sched == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Cpus_allowed_list:          %s\n", sched.CpusAllowedList))
+	sb.WriteString(fmt.Sprintf("voluntary_ctxt_switches:    %d\n", sched.VoluntaryCtxtSwitches))
+	sb.WriteString(fmt.Sprintf("nonvoluntary_ctxt_switches: %d", sched.NonvoluntaryCtxtSwitches))
 	return sb.String()
 }
 
+// SeccompModeName 返回 seccomp 模式的名称
+func SeccompModeName(mode int) string {
+	switch mode {
+	case 0:
+		return "disabled"
+	case 1:
+		return "strict"
+	case 2:
+		return "filter"
+	default:
+		return "unknown"
+	}
+}
+
+// formatIDs 格式化 Uid/Gid 行
+func formatIDs(ids IDs) string {
+	return fmt.Sprintf("%d %d %d %d (real/effective/saved/fs)", ids.Real, ids.Effective, ids.Saved, ids.FS)
+}
+
+// formatIntList 将整数列表格式化为空格分隔的字符串
+func formatIntList(list []int) string {
+	strs := make([]string, len(list))
+	for i, v := range list {
+		strs[i] = fmt.Sprintf("%d", v)
+	}
+	return strings.Join(strs, " ")
+}
+
 // FormatProcessStatusCompact 格式化进程状态为紧凑格式
 func FormatProcessStatusCompact(status *ProcessStatus, maxPIDLen int) string {
 	var sb strings.Builder
@@ -56,3 +152,88 @@ func FormatProcessStatusCompact(status *ProcessStatus, maxPIDLen int) string {
 
 	return sb.String()
 }
+
+// 表格中每列最多显示的名称数
+const maxCompactNames = 6
+
+// FormatProcessTable 将多个进程格式化为表格，每个进程一行
+// capability 和信号名称省略 CAP_/SIG 前缀以保持紧凑
+func FormatProcessTable(statuses []*ProcessStatus, opts ParseOptions) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	header := []string{"PID", "NAME", "STATE", "UID"}
+	if opts.ParseCapabilities {
+		header = append(header, "CAPEFF")
+	}
+	if opts.ParseSignals {
+		header = append(header, "SIGBLK", "SIGIGN", "SIGCGT")
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for _, status := range statuses {
+		state := status.State
+		if i := strings.IndexByte(state, ' '); i > 0 {
+			state = state[:i]
+		}
+		row := []string{
+			fmt.Sprintf("%d", status.PID),
+			status.Name,
+			state,
+			fmt.Sprintf("%d", status.UID.Effective),
+		}
+		if opts.ParseCapabilities {
+			row = append(row, compactCapabilities(status.Capabilities))
+		}
+		if opts.ParseSignals {
+			if status.Signals != nil {
+				row = append(row,
+					compactNames(DecodeSignalMask(status.Signals.Blocked), "SIG"),
+					compactNames(DecodeSignalMask(status.Signals.Ignored), "SIG"),
+					compactNames(DecodeSignalMask(status.Signals.Caught), "SIG"))
+			} else {
+				row = append(row, "-", "-", "-")
+			}
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	w.Flush()
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// compactCapabilities 返回 CapEff 的紧凑表示，包含所有已知 capability 时显示为 all
+func compactCapabilities(caps *CapabilitiesInfo) string {
+	if caps == nil {
+		return "-"
+	}
+	all := knownCapabilityMask()
+	if caps.Effective&all == all {
+		// 内核支持但本工具未收录的位单独列出
+		if unknown := UnknownCapabilityBits(caps.Effective); len(unknown) > 0 {
+			return "all+" + formatIntList(unknown)
+		}
+		return "all"
+	}
+	return compactNames(DecodeCapabilityMask(caps.Effective), "CAP_")
+}
+
+// compactNames 去掉名称前缀并转为小写后用逗号拼接
+// 超过 maxCompactNames 个时只显示前几个，其余以 +N 表示
+func compactNames(names []string, prefix string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	n := len(names)
+	if n > maxCompactNames {
+		n = maxCompactNames
+	}
+	short := make([]string, n)
+	for i := range short {
+		short[i] = strings.ToLower(strings.TrimPrefix(names[i], prefix))
+	}
+	if len(names) > n {
+		short = append(short, fmt.Sprintf("+%d", len(names)-n))
+	}
+	return strings.Join(short, ",")
+}
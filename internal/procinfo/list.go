@@ -0,0 +1,124 @@
+package procinfo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ListProcStatus 解析本机 /proc 下所有进程的状态，按 PID 排序
+// 遍历过程中退出的进程会被跳过
+func ListProcStatus(opts ParseOptions) ([]*ProcessStatus, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read /proc: %w", err)
+	}
+
+	var pids []int
+	for _, e := range entries {
+		if pid, err := strconv.Atoi(e.Name()); err == nil && e.IsDir() {
+			pids = append(pids, pid)
+		}
+	}
+	sort.Ints(pids)
+
+	statuses := make([]*ProcessStatus, 0, len(pids))
+	for _, pid := range pids {
+		status, err := ParseProcStatus(pid, opts)
+		if err != nil {
+			if opts.Verbose {
+				fmt.Fprintf(os.Stderr, "[DEBUG] skip pid %d: %v\n", pid, err)
+			}
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// SplitStatusContent 将多个 /proc/<pid>/status 拼接在一起的内容
+// (如 cat /proc/[0-9]*/status 的输出) 按 "Name:" 行拆分为单个进程的内容
+func SplitStatusContent(r io.Reader) ([][]byte, error) {
+	var chunks [][]byte
+	var current bytes.Buffer
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "Name:") && current.Len() > 0 {
+			chunks = append(chunks, bytes.Clone(current.Bytes()))
+			current.Reset()
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.Bytes())
+	}
+	return chunks, nil
+}
+
+// ParseStatusContent 拆分并解析拼接在一起的多个 status 内容
+// 读取过程中退出的进程可能只留下前几行，没有 Pid 行的片段会被跳过
+func ParseStatusContent(r io.Reader, opts ParseOptions) ([]*ProcessStatus, error) {
+	chunks, err := SplitStatusContent(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split proc status: %w", err)
+	}
+
+	statuses := make([]*ProcessStatus, 0, len(chunks))
+	for _, chunk := range chunks {
+		status, err := ParseStatus(bytes.NewReader(chunk), opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proc status: %w", err)
+		}
+		if status.PID == 0 {
+			if opts.Verbose {
+				fmt.Fprintf(os.Stderr, "[DEBUG] skip truncated status of %q\n", status.Name)
+			}
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// ProcessFilter 进程过滤条件，零值字段表示不过滤
+type ProcessFilter struct {
+	Name         string   // 进程名包含该字符串
+	UID          int      // 有效 UID 等于该值，小于 0 表示不过滤
+	Capabilities []string // CapEff 中包含全部这些 capability
+}
+
+// Match 判断进程是否满足过滤条件
+func (f ProcessFilter) Match(status *ProcessStatus) (bool, error) {
+	if f.Name != "" && !strings.Contains(status.Name, f.Name) {
+		return false, nil
+	}
+	if f.UID >= 0 && status.UID.Effective != f.UID {
+		return false, nil
+	}
+	if len(f.Capabilities) > 0 {
+		if status.Capabilities == nil {
+			return false, nil
+		}
+		for _, name := range f.Capabilities {
+			bit, err := CapabilityBit(name)
+			if err != nil {
+				return false, err
+			}
+			if status.Capabilities.Effective&(1<<uint(bit)) == 0 {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
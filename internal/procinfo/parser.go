@@ -2,27 +2,47 @@ package procinfo
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 )
 
+// ErrProcessNotFound 进程不存在或已退出
+var ErrProcessNotFound = errors.New("process does not exist")
+
 // ParseProcStatus 解析 /proc/pid/status 文件
 func ParseProcStatus(pid int, opts ParseOptions) (*ProcessStatus, error) {
-	statusPath := fmt.Sprintf("/proc/%d/status", pid)
+	return parseStatusFile(fmt.Sprintf("/proc/%d/status", pid), pid, opts)
+}
+
+// parseStatusFile 解析指定路径的 status 文件，文件中没有 Pid 行时使用 pid
+func parseStatusFile(statusPath string, pid int, opts ParseOptions) (*ProcessStatus, error) {
 	file, err := os.Open(statusPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("process %d does not exist", pid)
+			return nil, fmt.Errorf("%w: %d", ErrProcessNotFound, pid)
 		}
 		return nil, fmt.Errorf("failed to open %s: %w", statusPath, err)
 	}
 	defer file.Close()
 
-	status := &ProcessStatus{
-		PID: pid,
+	status, err := ParseStatus(file, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", statusPath, err)
+	}
+	if status.PID == 0 {
+		status.PID = pid
 	}
+	return status, nil
+}
+
+// ParseStatus 从 r 中解析 /proc/<pid>/status 格式的内容
+// 本机文件、Pod 内 cat 的输出以及保存下来的样本都使用这个解析器，PID 取自 Pid 行
+func ParseStatus(r io.Reader, opts ParseOptions) (*ProcessStatus, error) {
+	status := &ProcessStatus{}
 
 	if opts.ParseCapabilities {
 		status.Capabilities = &CapabilitiesInfo{}
@@ -30,8 +50,17 @@ func ParseProcStatus(pid int, opts ParseOptions) (*ProcessStatus, error) {
 	if opts.ParseSignals {
 		status.Signals = &SignalsInfo{}
 	}
+	if opts.ParseMemory {
+		status.Memory = &MemoryInfo{}
+	}
+	if opts.ParseSecurity {
+		status.Security = &SecurityInfo{}
+	}
+	if opts.ParseScheduling {
+		status.Scheduling = &SchedulingInfo{}
+	}
 
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
 		fields := strings.SplitN(line, ":", 2)
@@ -47,6 +76,10 @@ func ParseProcStatus(pid int, opts ParseOptions) (*ProcessStatus, error) {
 			status.Name = value
 		case "State":
 			status.State = value
+		case "Pid":
+			if pid, err := strconv.Atoi(value); err == nil {
+				status.PID = pid
+			}
 
 		// Capabilities (需要启用 ParseCapabilities)
 		case "CapInh":
@@ -115,16 +148,27 @@ func ParseProcStatus(pid int, opts ParseOptions) (*ProcessStatus, error) {
 					status.Signals.Caught = mask
 				}
 			}
+		default:
+			parseField(status, key, value)
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to scan %s: %w", statusPath, err)
+		return nil, fmt.Errorf("failed to scan status: %w", err)
 	}
 
 	return status, nil
 }
 
+// ParseMask 解析十六进制 mask，可以带 0x 前缀，如 /proc 中的 "00000000a80425fb"
+func ParseMask(s string) (uint64, error) {
+	mask, err := parseHexUint64(strings.TrimPrefix(strings.TrimSpace(s), "0X"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid hex mask: %q", s)
+	}
+	return mask, nil
+}
+
 // parseHexUint64 解析十六进制字符串为 uint64
 func parseHexUint64(s string) (uint64, error) {
 	// 移除可能的 "0x" 前缀
@@ -133,3 +177,106 @@ func parseHexUint64(s string) (uint64, error) {
 
 	return strconv.ParseUint(s, 16, 64)
 }
+
+// parseField 解析 Name/State/Pid/Cap*/Sig* 以外的字段
+// 可选部分 (Memory/Security/Scheduling) 只有在已分配时才会填充
+func parseField(status *ProcessStatus, key, value string) {
+	switch key {
+	// 进程标识
+	case "PPid":
+		if v, err := strconv.Atoi(value); err == nil {
+			status.PPid = v
+		}
+	case "Threads":
+		if v, err := strconv.Atoi(value); err == nil {
+			status.Threads = v
+		}
+	case "NSpid":
+		status.NSpid = parseIntList(value)
+	case "NStgid":
+		status.NStgid = parseIntList(value)
+	case "Uid":
+		if ids, err := parseIDs(value); err == nil {
+			status.UID = ids
+		}
+	case "Gid":
+		if ids, err := parseIDs(value); err == nil {
+			status.GID = ids
+		}
+	case "Groups":
+		status.Groups = parseIntList(value)
+
+	// 内存
+	case "VmRSS":
+		if status.Memory != nil {
+			status.Memory.VmRSS = parseKB(value)
+		}
+	case "VmHWM":
+		if status.Memory != nil {
+			status.Memory.VmHWM = parseKB(value)
+		}
+
+	// 安全
+	case "Seccomp":
+		if status.Security != nil {
+			status.Security.Seccomp, _ = strconv.Atoi(value)
+		}
+	case "Seccomp_filters":
+		if status.Security != nil {
+			status.Security.SeccompFilters, _ = strconv.Atoi(value)
+		}
+	case "NoNewPrivs":
+		if status.Security != nil {
+			status.Security.NoNewPrivs = value == "1"
+		}
+
+	// 调度
+	case "Cpus_allowed_list":
+		if status.Scheduling != nil {
+			status.Scheduling.CpusAllowedList = value
+		}
+	case "voluntary_ctxt_switches":
+		if status.Scheduling != nil {
+			status.Scheduling.VoluntaryCtxtSwitches, _ = strconv.ParseUint(value, 10, 64)
+		}
+	case "nonvoluntary_ctxt_switches":
+		if status.Scheduling != nil {
+			status.Scheduling.NonvoluntaryCtxtSwitches, _ = strconv.ParseUint(value, 10, 64)
+		}
+	}
+}
+
+// parseIntList 解析空白分隔的整数列表，如 Groups 和 NSpid
+func parseIntList(s string) []int {
+	var list []int
+	for _, f := range strings.Fields(s) {
+		if v, err := strconv.Atoi(f); err == nil {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// parseKB 解析 "1244 kB" 格式的内存大小
+func parseKB(s string) uint64 {
+	v, _ := strconv.ParseUint(strings.TrimSpace(strings.TrimSuffix(s, "kB")), 10, 64)
+	return v
+}
+
+// parseIDs 解析 Uid/Gid 行的 "real effective saved fs" 四个 ID
+func parseIDs(s string) (IDs, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 4 {
+		return IDs{}, fmt.Errorf("invalid id list: %q", s)
+	}
+
+	var ids [4]int
+	for i, f := range fields {
+		id, err := strconv.Atoi(f)
+		if err != nil {
+			return IDs{}, err
+		}
+		ids[i] = id
+	}
+	return IDs{Real: ids[0], Effective: ids[1], Saved: ids[2], FS: ids[3]}, nil
+}
@@ -0,0 +1,250 @@
+package procinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// allOptions 启用所有部分的解析选项
+func allOptions(t *testing.T) ParseOptions {
+	t.Helper()
+	opts, err := OptionsForSections(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return opts
+}
+
+// openFixture 打开 testdata 下的样本文件
+func openFixture(t *testing.T, name string) *os.File {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    ProcessStatus
+	}{
+		{
+			fixture: "normal.status",
+			want: ProcessStatus{
+				Name:    "nginx",
+				State:   "S (sleeping)",
+				PID:     1234,
+				PPid:    1,
+				Threads: 1,
+				NSpid:   []int{1234, 7},
+				NStgid:  []int{1234, 7},
+				UID:     IDs{Real: 0, Effective: 0, Saved: 0, FS: 0},
+				GID:     IDs{Real: 0, Effective: 0, Saved: 0, FS: 0},
+				Groups:  []int{0, 1, 2, 3, 4, 6, 10, 11, 20, 26, 27},
+				Capabilities: &CapabilitiesInfo{
+					Permitted: 0xa80425fb,
+					Effective: 0xa80425fb,
+					Bounding:  0xa80425fb,
+				},
+				Signals: &SignalsInfo{
+					Queued:  "0/63457",
+					Ignored: 0x1000,
+					Caught:  0x4002,
+				},
+				Memory:     &MemoryInfo{VmRSS: 6912, VmHWM: 7424},
+				Security:   &SecurityInfo{Seccomp: 2, SeccompFilters: 1, NoNewPrivs: true},
+				Scheduling: &SchedulingInfo{CpusAllowedList: "0-3", VoluntaryCtxtSwitches: 152, NonvoluntaryCtxtSwitches: 9},
+			},
+		},
+		{
+			fixture: "newer_kernel.status",
+			want: ProcessStatus{
+				Name:    "kubelet",
+				State:   "S (sleeping)",
+				PID:     812,
+				PPid:    1,
+				Threads: 1,
+				NSpid:   []int{812},
+				NStgid:  []int{812},
+				Groups:  []int{0, 1, 2, 3, 4, 6, 10, 11, 20, 26, 27},
+				Capabilities: &CapabilitiesInfo{
+					Permitted: 0x7ffffffffff,
+					Effective: 0x7ffffffffff,
+					Bounding:  0x7ffffffffff,
+				},
+				Signals: &SignalsInfo{
+					Queued:  "0/63457",
+					Ignored: 0x1000,
+					Caught:  0x4002,
+				},
+				Memory:     &MemoryInfo{VmRSS: 6912, VmHWM: 7424},
+				Security:   &SecurityInfo{},
+				Scheduling: &SchedulingInfo{CpusAllowedList: "0-3", VoluntaryCtxtSwitches: 152, NonvoluntaryCtxtSwitches: 9},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got, err := ParseStatus(openFixture(t, tt.fixture), allOptions(t))
+			if err != nil {
+				t.Fatalf("ParseStatus: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ParseStatus mismatch:\n got: %+v\nwant: %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStatusSkipsDisabledSections(t *testing.T) {
+	got, err := ParseStatus(openFixture(t, "normal.status"), ParseOptions{ParseCapabilities: true})
+	if err != nil {
+		t.Fatalf("ParseStatus: %v", err)
+	}
+	if got.Capabilities == nil || got.Capabilities.Effective != 0xa80425fb {
+		t.Errorf("Capabilities = %+v, want CapEff 0xa80425fb", got.Capabilities)
+	}
+	if got.Signals != nil || got.Memory != nil || got.Security != nil || got.Scheduling != nil {
+		t.Errorf("disabled sections were parsed: %+v", got)
+	}
+}
+
+func TestDecodeCapabilityMask(t *testing.T) {
+	tests := []struct {
+		name        string
+		mask        uint64
+		want        []string
+		wantUnknown []int
+	}{
+		{name: "empty", mask: 0},
+		{name: "single", mask: 1 << 21, want: []string{"CAP_SYS_ADMIN"}},
+		{
+			name: "docker default",
+			mask: 0xa80425fb,
+			want: []string{
+				"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_FOWNER", "CAP_FSETID", "CAP_KILL",
+				"CAP_SETGID", "CAP_SETUID", "CAP_SETPCAP", "CAP_NET_BIND_SERVICE", "CAP_NET_RAW",
+				"CAP_SYS_CHROOT", "CAP_MKNOD", "CAP_AUDIT_WRITE", "CAP_SETFCAP",
+			},
+		},
+		{
+			name:        "last known and beyond",
+			mask:        1<<40 | 1<<41 | 1<<63,
+			want:        []string{"CAP_CHECKPOINT_RESTORE", "CAP_UNKNOWN_41", "CAP_UNKNOWN_63"},
+			wantUnknown: []int{41, 63},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecodeCapabilityMask(tt.mask); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeCapabilityMask(%#x) = %v, want %v", tt.mask, got, tt.want)
+			}
+			if got := UnknownCapabilityBits(tt.mask); !reflect.DeepEqual(got, tt.wantUnknown) {
+				t.Errorf("UnknownCapabilityBits(%#x) = %v, want %v", tt.mask, got, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestUnknownCapabilitiesFromNewerKernel(t *testing.T) {
+	status, err := ParseStatus(openFixture(t, "newer_kernel.status"), ParseOptions{ParseCapabilities: true})
+	if err != nil {
+		t.Fatalf("ParseStatus: %v", err)
+	}
+
+	names := DecodeCapabilityMask(status.Capabilities.Bounding)
+	if len(names) != 43 {
+		t.Fatalf("decoded %d capabilities, want 43: %v", len(names), names)
+	}
+	if got, want := names[40:], []string{"CAP_CHECKPOINT_RESTORE", "CAP_UNKNOWN_41", "CAP_UNKNOWN_42"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("last capabilities = %v, want %v", got, want)
+	}
+	if got := UnknownCapabilityBits(status.Capabilities.Bounding); !reflect.DeepEqual(got, []int{41, 42}) {
+		t.Errorf("UnknownCapabilityBits = %v, want [41 42]", got)
+	}
+
+	// 未知位的名称可以作为过滤条件使用
+	for _, name := range []string{"CAP_UNKNOWN_42", "unknown_42"} {
+		bit, err := CapabilityBit(name)
+		if err != nil || bit != 42 {
+			t.Errorf("CapabilityBit(%q) = %d, %v; want 42", name, bit, err)
+		}
+	}
+
+	formatted := FormatCapabilitiesInfo(status.Capabilities)
+	if !strings.Contains(formatted, "bits 41 42 are unknown") {
+		t.Errorf("FormatCapabilitiesInfo does not mention unknown bits:\n%s", formatted)
+	}
+}
+
+func TestSplitStatusContent(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantNames []string
+	}{
+		{name: "empty", input: ""},
+		{name: "single", input: "Name:\tsh\nPid:\t1\n", wantNames: []string{"sh"}},
+		{name: "no trailing newline", input: "Name:\tsh\nPid:\t1\nName:\tcat\nPid:\t2", wantNames: []string{"sh", "cat"}},
+		{name: "truncated in the middle", input: "Name:\tsh\nPid:\t1\nName:\tgone\nUmask:\t0022\nName:\tcat\nPid:\t2\n", wantNames: []string{"sh", "gone", "cat"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks, err := SplitStatusContent(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("SplitStatusContent: %v", err)
+			}
+			var names []string
+			for _, chunk := range chunks {
+				first, _, _ := strings.Cut(string(chunk), "\n")
+				names = append(names, strings.TrimSpace(strings.TrimPrefix(first, "Name:")))
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("chunk names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestParseStatusContent(t *testing.T) {
+	chunks, err := SplitStatusContent(openFixture(t, "concatenated.status"))
+	if err != nil {
+		t.Fatalf("SplitStatusContent: %v", err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("split into %d chunks, want 3 (including the vanished process)", len(chunks))
+	}
+
+	statuses, err := ParseStatusContent(openFixture(t, "concatenated.status"), allOptions(t))
+	if err != nil {
+		t.Fatalf("ParseStatusContent: %v", err)
+	}
+
+	type summary struct {
+		Name   string
+		PID    int
+		PPid   int
+		UID    int
+		CapEff uint64
+	}
+	var got []summary
+	for _, s := range statuses {
+		got = append(got, summary{s.Name, s.PID, s.PPid, s.UID.Effective, s.Capabilities.Effective})
+	}
+	want := []summary{
+		{Name: "tini", PID: 1, PPid: 0, UID: 0, CapEff: 0xa80425fb},
+		{Name: "sh", PID: 42, PPid: 1, UID: 1000, CapEff: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsed processes = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package procinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 可以通过 --section 选择的输出部分
+const (
+	SectionIdentity     = "identity"
+	SectionCapabilities = "capabilities"
+	SectionSignals      = "signals"
+	SectionMemory       = "memory"
+	SectionSecurity     = "security"
+	SectionScheduling   = "scheduling"
+)
+
+// AllSections 所有输出部分，顺序与 FormatProcessStatus 的输出顺序一致
+var AllSections = []string{
+	SectionIdentity,
+	SectionCapabilities,
+	SectionSignals,
+	SectionMemory,
+	SectionSecurity,
+	SectionScheduling,
+}
+
+// OptionsForSections 根据选择的部分生成解析选项，sections 为空时选择所有部分
+func OptionsForSections(sections []string) (ParseOptions, error) {
+	if len(sections) == 0 {
+		sections = AllSections
+	}
+
+	var opts ParseOptions
+	for _, s := range sections {
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case SectionIdentity:
+			opts.ParseIdentity = true
+		case SectionCapabilities, "caps":
+			opts.ParseCapabilities = true
+		case SectionSignals:
+			opts.ParseSignals = true
+		case SectionMemory:
+			opts.ParseMemory = true
+		case SectionSecurity:
+			opts.ParseSecurity = true
+		case SectionScheduling, "sched":
+			opts.ParseScheduling = true
+		default:
+			return ParseOptions{}, fmt.Errorf("unknown section: %s (valid: %s)", s, strings.Join(AllSections, ", "))
+		}
+	}
+	return opts, nil
+}
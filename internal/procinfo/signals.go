@@ -2,98 +2,323 @@ package procinfo
 
 import (
 	"fmt"
+	"runtime"
+	"strconv"
 	"strings"
-	"syscall"
 )
 
-// Linux 信号名称映射 (基于 signal(7))
-var signalNames = map[int]string{
-	1:  "SIGHUP",
-	2:  "SIGINT",
-	3:  "SIGQUIT",
-	4:  "SIGILL",
-	5:  "SIGTRAP",
-	6:  "SIGABRT",
-	7:  "SIGBUS",
-	8:  "SIGFPE",
-	9:  "SIGKILL",
-	10: "SIGUSR1",
-	11: "SIGSEGV",
-	12: "SIGUSR2",
-	13: "SIGPIPE",
-	14: "SIGALRM",
-	15: "SIGTERM",
-	16: "SIGSTKFLT",
-	17: "SIGCHLD",
-	18: "SIGCONT",
-	19: "SIGSTOP",
-	20: "SIGTSTP",
-	21: "SIGTTIN",
-	22: "SIGTTOU",
-	23: "SIGURG",
-	24: "SIGXCPU",
-	25: "SIGXFSZ",
-	26: "SIGVTALRM",
-	27: "SIGPROF",
-	28: "SIGWINCH",
-	29: "SIGIO",
-	30: "SIGPWR",
-	31: "SIGSYS",
-	34: "SIGRTMIN",
-	35: "SIGRTMIN+1",
-	36: "SIGRTMIN+2",
-	37: "SIGRTMIN+3",
-	38: "SIGRTMIN+4",
-	39: "SIGRTMIN+5",
-	40: "SIGRTMIN+6",
-	41: "SIGRTMIN+7",
-	42: "SIGRTMIN+8",
-	43: "SIGRTMIN+9",
-	44: "SIGRTMIN+10",
-	45: "SIGRTMIN+11",
-	46: "SIGRTMIN+12",
-	47: "SIGRTMIN+13",
-	48: "SIGRTMIN+14",
-	49: "SIGRTMIN+15",
-	50: "SIGRTMAX-14",
-	51: "SIGRTMAX-13",
-	52: "SIGRTMAX-12",
-	53: "SIGRTMAX-11",
-	54: "SIGRTMAX-10",
-	55: "SIGRTMAX-9",
-	56: "SIGRTMAX-8",
-	57: "SIGRTMAX-7",
-	58: "SIGRTMAX-6",
-	59: "SIGRTMAX-5",
-	60: "SIGRTMAX-4",
-	61: "SIGRTMAX-3",
-	62: "SIGRTMAX-2",
-	63: "SIGRTMAX-1",
-	64: "SIGRTMAX",
-}
-
-// DecodeSignalMask 解码信号 bitmask 为可读名称列表
-func DecodeSignalMask(mask uint64) []string {
+const (
+	// maxMaskSignal /proc/<pid>/status 中的信号 mask 是 64 位，只能表示 1-64 号信号
+	maxMaskSignal = 64
+	// firstRTSignal 内核的 SIGRTMIN，所有体系结构相同
+	firstRTSignal = 32
+	// userRTMin glibc 的 SIGRTMIN，32 和 33 被 NPTL 内部使用，kill -l 和 SIGRTMIN+n 都以 34 为基准
+	userRTMin = 34
+)
+
+// SignalArchs 支持的信号编号体系结构
+// generic 适用于 x86、arm、arm64、riscv、powerpc、s390 等，mips、alpha、sparc 的经典信号编号不同
+var SignalArchs = []string{"generic", "mips", "alpha", "sparc"}
+
+// SignalTable 一个体系结构的信号编号表 (基于 signal(7))
+type SignalTable struct {
+	Arch    string
+	names   map[int]string // 经典信号 1-31
+	aliases map[string]int // 同一信号的其他名称，只用于名称查找
+	rtMax   int            // 内核的 SIGRTMAX (_NSIG)，mips 为 128
+}
+
+var genericSignals = &SignalTable{
+	Arch: "generic",
+	names: map[int]string{
+		1:  "SIGHUP",
+		2:  "SIGINT",
+		3:  "SIGQUIT",
+		4:  "SIGILL",
+		5:  "SIGTRAP",
+		6:  "SIGABRT",
+		7:  "SIGBUS",
+		8:  "SIGFPE",
+		9:  "SIGKILL",
+		10: "SIGUSR1",
+		11: "SIGSEGV",
+		12: "SIGUSR2",
+		13: "SIGPIPE",
+		14: "SIGALRM",
+		15: "SIGTERM",
+		16: "SIGSTKFLT",
+		17: "SIGCHLD",
+		18: "SIGCONT",
+		19: "SIGSTOP",
+		20: "SIGTSTP",
+		21: "SIGTTIN",
+		22: "SIGTTOU",
+		23: "SIGURG",
+		24: "SIGXCPU",
+		25: "SIGXFSZ",
+		26: "SIGVTALRM",
+		27: "SIGPROF",
+		28: "SIGWINCH",
+		29: "SIGIO",
+		30: "SIGPWR",
+		31: "SIGSYS",
+	},
+	aliases: map[string]int{"SIGIOT": 6, "SIGCLD": 17, "SIGPOLL": 29, "SIGUNUSED": 31},
+	rtMax:   64,
+}
+
+// alpha 与 sparc 的编号相同，只有 29 号的别名不同
+var alphaSignals = &SignalTable{
+	Arch:    "alpha",
+	names:   bsdSignalNames(),
+	aliases: map[string]int{"SIGIOT": 6, "SIGCLD": 20, "SIGPOLL": 23, "SIGINFO": 29},
+	rtMax:   64,
+}
+
+var sparcSignals = &SignalTable{
+	Arch:    "sparc",
+	names:   bsdSignalNames(),
+	aliases: map[string]int{"SIGIOT": 6, "SIGCLD": 20, "SIGPOLL": 23, "SIGLOST": 29},
+	rtMax:   64,
+}
+
+var mipsSignals = &SignalTable{
+	Arch: "mips",
+	names: map[int]string{
+		1:  "SIGHUP",
+		2:  "SIGINT",
+		3:  "SIGQUIT",
+		4:  "SIGILL",
+		5:  "SIGTRAP",
+		6:  "SIGABRT",
+		7:  "SIGEMT",
+		8:  "SIGFPE",
+		9:  "SIGKILL",
+		10: "SIGBUS",
+		11: "SIGSEGV",
+		12: "SIGSYS",
+		13: "SIGPIPE",
+		14: "SIGALRM",
+		15: "SIGTERM",
+		16: "SIGUSR1",
+		17: "SIGUSR2",
+		18: "SIGCHLD",
+		19: "SIGPWR",
+		20: "SIGWINCH",
+		21: "SIGURG",
+		22: "SIGIO",
+		23: "SIGSTOP",
+		24: "SIGTSTP",
+		25: "SIGCONT",
+		26: "SIGTTIN",
+		27: "SIGTTOU",
+		28: "SIGVTALRM",
+		29: "SIGPROF",
+		30: "SIGXCPU",
+		31: "SIGXFSZ",
+	},
+	aliases: map[string]int{"SIGIOT": 6, "SIGCLD": 18, "SIGPOLL": 22},
+	rtMax:   128,
+}
+
+// bsdSignalNames 返回 alpha 和 sparc 共用的经典信号编号
+func bsdSignalNames() map[int]string {
+	return map[int]string{
+		1:  "SIGHUP",
+		2:  "SIGINT",
+		3:  "SIGQUIT",
+		4:  "SIGILL",
+		5:  "SIGTRAP",
+		6:  "SIGABRT",
+		7:  "SIGEMT",
+		8:  "SIGFPE",
+		9:  "SIGKILL",
+		10: "SIGBUS",
+		11: "SIGSEGV",
+		12: "SIGSYS",
+		13: "SIGPIPE",
+		14: "SIGALRM",
+		15: "SIGTERM",
+		16: "SIGURG",
+		17: "SIGSTOP",
+		18: "SIGTSTP",
+		19: "SIGCONT",
+		20: "SIGCHLD",
+		21: "SIGTTIN",
+		22: "SIGTTOU",
+		23: "SIGIO",
+		24: "SIGXCPU",
+		25: "SIGXFSZ",
+		26: "SIGVTALRM",
+		27: "SIGPROF",
+		28: "SIGWINCH",
+		29: "SIGPWR",
+		30: "SIGUSR1",
+		31: "SIGUSR2",
+	}
+}
+
+// hostSignals 本工具运行所在体系结构的信号表，包级函数都使用它
+var hostSignals = mustSignalTable(runtime.GOARCH)
+
+// SignalTableFor 返回指定体系结构的信号表
+// 接受 SignalArchs 中的名称和 GOARCH 值 (如 amd64、mips64le)，为空时返回本机的信号表
+func SignalTableFor(arch string) (*SignalTable, error) {
+	arch = strings.ToLower(strings.TrimSpace(arch))
+	if arch == "" {
+		return hostSignals, nil
+	}
+	if t := lookupSignalTable(arch); t != nil {
+		return t, nil
+	}
+	return nil, fmt.Errorf("unsupported signal architecture: %s (supported: %s)", arch, strings.Join(SignalArchs, ", "))
+}
+
+// lookupSignalTable 按体系结构名称查找信号表，未知名称返回 nil
+func lookupSignalTable(arch string) *SignalTable {
+	switch arch {
+	case "generic", "386", "amd64", "x86", "x86_64", "arm", "arm64", "aarch64",
+		"riscv64", "ppc64", "ppc64le", "s390x", "loong64", "wasm":
+		return genericSignals
+	case "mips", "mipsle", "mips64", "mips64le":
+		return mipsSignals
+	case "alpha":
+		return alphaSignals
+	case "sparc", "sparc64":
+		return sparcSignals
+	}
+	return nil
+}
+
+// mustSignalTable 返回 GOARCH 对应的信号表，未知体系结构使用 generic
+func mustSignalTable(goarch string) *SignalTable {
+	if t := lookupSignalTable(goarch); t != nil {
+		return t
+	}
+	return genericSignals
+}
+
+// Name 返回信号编号对应的名称
+// 实时信号按 kill -l 的习惯以 glibc 的 SIGRTMIN (34) 为基准命名为 SIGRTMIN+n，
+// 32、33 显示为 SIGRTMIN-2、SIGRTMIN-1，内核的最后一个信号显示为 SIGRTMAX
+func (t *SignalTable) Name(signum int) string {
+	if name, ok := t.names[signum]; ok {
+		return name
+	}
+	switch {
+	case signum < firstRTSignal || signum > t.rtMax:
+		return fmt.Sprintf("signal %d", signum)
+	case signum == t.rtMax:
+		return "SIGRTMAX"
+	case signum == userRTMin:
+		return "SIGRTMIN"
+	case signum > userRTMin:
+		return fmt.Sprintf("SIGRTMIN+%d", signum-userRTMin)
+	default:
+		return fmt.Sprintf("SIGRTMIN-%d", userRTMin-signum)
+	}
+}
+
+// Decode 解码信号 bitmask 为可读名称列表
+func (t *SignalTable) Decode(mask uint64) []string {
 	if mask == 0 {
 		return nil
 	}
 
 	var signals []string
-	// Linux 信号从 1 到 64
-	for signum := 1; signum <= 64; signum++ {
+	for signum := 1; signum <= maxMaskSignal; signum++ {
 		if mask&(1<<uint(signum-1)) != 0 {
-			if name, ok := signalNames[signum]; ok {
-				signals = append(signals, name)
-			} else {
-				// 对于未知信号，使用 syscall 包尝试获取名称
-				sig := syscall.Signal(signum)
-				signals = append(signals, sig.String())
-			}
+			signals = append(signals, t.Name(signum))
 		}
 	}
 	return signals
 }
 
+// Number 根据名称查找信号编号
+// 名称不区分大小写，可以省略 SIG 前缀，也接受数字、SIGRTMIN+n、SIGRTMAX-n 和 Decode 输出的 "signal N"
+func (t *SignalTable) Number(name string) (int, error) {
+	upper := strings.ToUpper(strings.TrimSpace(name))
+	if rest, ok := strings.CutPrefix(upper, "SIGNAL "); ok {
+		upper = rest
+	}
+	if n, err := strconv.Atoi(upper); err == nil {
+		return checkMaskSignal(n, name)
+	}
+
+	if !strings.HasPrefix(upper, "SIG") {
+		upper = "SIG" + upper
+	}
+	for signum, n := range t.names {
+		if n == upper {
+			return signum, nil
+		}
+	}
+	if signum, ok := t.aliases[upper]; ok {
+		return signum, nil
+	}
+
+	if rest, ok := strings.CutPrefix(upper, "SIGRTMIN"); ok {
+		if offset, ok := parseSignalOffset(rest); ok {
+			return checkMaskSignal(userRTMin+offset, name)
+		}
+	}
+	if rest, ok := strings.CutPrefix(upper, "SIGRTMAX"); ok {
+		if offset, ok := parseSignalOffset(rest); ok && offset <= 0 {
+			return checkMaskSignal(t.rtMax+offset, name)
+		}
+	}
+	return 0, fmt.Errorf("unknown signal: %s", name)
+}
+
+// parseSignalOffset 解析 SIGRTMIN/SIGRTMAX 之后的 "+n"、"-n" 或空字符串
+func parseSignalOffset(s string) (int, bool) {
+	if s == "" {
+		return 0, true
+	}
+	if s[0] != '+' && s[0] != '-' {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[1:])
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	if s[0] == '-' {
+		n = -n
+	}
+	return n, true
+}
+
+// checkMaskSignal 检查信号编号是否能在 64 位 mask 中表示
+func checkMaskSignal(signum int, name string) (int, error) {
+	if signum < 1 || signum > maxMaskSignal {
+		return 0, fmt.Errorf("signal number out of range (1-%d): %s", maxMaskSignal, name)
+	}
+	return signum, nil
+}
+
+// Encode 将信号名称列表编码为 bitmask，名称规则同 Number
+func (t *SignalTable) Encode(names []string) (uint64, error) {
+	var mask uint64
+	for _, name := range names {
+		signum, err := t.Number(name)
+		if err != nil {
+			return 0, err
+		}
+		mask |= 1 << uint(signum-1)
+	}
+	return mask, nil
+}
+
+// MaskDocument 创建信号 mask 的文档表示
+func (t *SignalTable) MaskDocument(mask uint64) MaskDocument {
+	return newMaskDocument(mask, t.Decode(mask))
+}
+
+// DecodeSignalMask 使用本机的信号表解码信号 bitmask 为可读名称列表
+func DecodeSignalMask(mask uint64) []string {
+	return hostSignals.Decode(mask)
+}
+
 // FormatSignalMask 格式化信号 mask 为可读字符串
 func FormatSignalMask(mask uint64) string {
 	if mask == 0 {
@@ -119,3 +344,13 @@ func FormatSignalsInfo(sigs *SignalsInfo) string {
 	sb.WriteString(fmt.Sprintf("SigCgt: 0x%016x -> %s", sigs.Caught, FormatSignalMask(sigs.Caught)))
 	return sb.String()
 }
+
+// SignalNumber 使用本机的信号表根据名称查找信号编号，名称规则见 SignalTable.Number
+func SignalNumber(name string) (int, error) {
+	return hostSignals.Number(name)
+}
+
+// EncodeSignalMask 使用本机的信号表将信号名称列表编码为 bitmask
+func EncodeSignalMask(names []string) (uint64, error) {
+	return hostSignals.Encode(names)
+}
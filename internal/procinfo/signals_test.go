@@ -0,0 +1,185 @@
+package procinfo
+
+import "testing"
+
+// mustTable 返回指定体系结构的信号表
+func mustTable(t *testing.T, arch string) *SignalTable {
+	t.Helper()
+	table, err := SignalTableFor(arch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestSignalTableFor(t *testing.T) {
+	tests := []struct {
+		arch     string
+		wantArch string
+		wantErr  bool
+	}{
+		{arch: "generic", wantArch: "generic"},
+		{arch: "amd64", wantArch: "generic"},
+		{arch: "arm64", wantArch: "generic"},
+		{arch: " MIPS ", wantArch: "mips"},
+		{arch: "mips64le", wantArch: "mips"},
+		{arch: "alpha", wantArch: "alpha"},
+		{arch: "sparc64", wantArch: "sparc"},
+		{arch: "pdp11", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arch, func(t *testing.T) {
+			table, err := SignalTableFor(tt.arch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SignalTableFor(%q) error = %v, wantErr %t", tt.arch, err, tt.wantErr)
+			}
+			if err == nil && table.Arch != tt.wantArch {
+				t.Errorf("SignalTableFor(%q) = %s, want %s", tt.arch, table.Arch, tt.wantArch)
+			}
+		})
+	}
+}
+
+func TestSignalNumbersPerArch(t *testing.T) {
+	tests := []struct {
+		arch   string
+		name   string
+		signum int
+	}{
+		{"generic", "SIGUSR1", 10},
+		{"mips", "SIGUSR1", 16},
+		{"alpha", "SIGUSR1", 30},
+		{"sparc", "SIGUSR1", 30},
+		{"generic", "SIGCHLD", 17},
+		{"mips", "SIGCHLD", 18},
+		{"alpha", "SIGCHLD", 20},
+		{"generic", "SIGSTOP", 19},
+		{"mips", "SIGSTOP", 23},
+		{"sparc", "SIGSTOP", 17},
+		{"generic", "SIGBUS", 7},
+		{"mips", "SIGBUS", 10},
+		{"mips", "SIGEMT", 7},
+		{"generic", "SIGTERM", 15},
+		{"mips", "SIGTERM", 15},
+		{"alpha", "SIGTERM", 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arch+"/"+tt.name, func(t *testing.T) {
+			table := mustTable(t, tt.arch)
+			if got := table.Name(tt.signum); got != tt.name {
+				t.Errorf("Name(%d) = %s, want %s", tt.signum, got, tt.name)
+			}
+			if got, err := table.Number(tt.name); err != nil || got != tt.signum {
+				t.Errorf("Number(%s) = %d, %v; want %d", tt.name, got, err, tt.signum)
+			}
+		})
+	}
+}
+
+func TestSignalNumberNames(t *testing.T) {
+	tests := []struct {
+		arch    string
+		name    string
+		want    int
+		wantErr bool
+	}{
+		// 名称格式
+		{arch: "generic", name: "usr1", want: 10},
+		{arch: "generic", name: " sigterm ", want: 15},
+		{arch: "generic", name: "9", want: 9},
+		{arch: "generic", name: "signal 40", want: 40},
+		{arch: "generic", name: "SIGNOPE", wantErr: true},
+		{arch: "generic", name: "0", wantErr: true},
+		{arch: "generic", name: "65", wantErr: true},
+
+		// 别名
+		{arch: "generic", name: "SIGIOT", want: 6},
+		{arch: "generic", name: "SIGCLD", want: 17},
+		{arch: "mips", name: "SIGCLD", want: 18},
+		{arch: "alpha", name: "SIGINFO", want: 29},
+		{arch: "sparc", name: "SIGLOST", want: 29},
+		{arch: "sparc", name: "SIGINFO", wantErr: true},
+
+		// 实时信号
+		{arch: "generic", name: "SIGRTMIN", want: 34},
+		{arch: "generic", name: "rtmin+3", want: 37},
+		{arch: "generic", name: "SIGRTMIN-2", want: 32},
+		{arch: "generic", name: "SIGRTMAX", want: 64},
+		{arch: "generic", name: "SIGRTMAX-1", want: 63},
+		{arch: "generic", name: "SIGRTMAX+1", wantErr: true},
+		{arch: "generic", name: "SIGRTMIN+31", wantErr: true},
+		{arch: "mips", name: "SIGRTMIN+30", want: 64},
+		{arch: "mips", name: "SIGRTMAX", wantErr: true}, // 128 超出 64 位 mask
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arch+"/"+tt.name, func(t *testing.T) {
+			got, err := mustTable(t, tt.arch).Number(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Number(%q) error = %v, wantErr %t", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Number(%q) = %d, want %d", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealtimeSignalNames(t *testing.T) {
+	tests := []struct {
+		arch   string
+		signum int
+		want   string
+	}{
+		{"generic", 31, "SIGSYS"},
+		{"generic", 32, "SIGRTMIN-2"},
+		{"generic", 33, "SIGRTMIN-1"},
+		{"generic", 34, "SIGRTMIN"},
+		{"generic", 35, "SIGRTMIN+1"},
+		{"generic", 63, "SIGRTMIN+29"},
+		{"generic", 64, "SIGRTMAX"},
+		{"generic", 65, "signal 65"},
+		{"generic", 0, "signal 0"},
+		{"mips", 31, "SIGXFSZ"},
+		{"mips", 34, "SIGRTMIN"},
+		{"mips", 64, "SIGRTMIN+30"}, // mips 的 SIGRTMAX 是 128
+		{"mips", 128, "SIGRTMAX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arch+"/"+tt.want, func(t *testing.T) {
+			if got := mustTable(t, tt.arch).Name(tt.signum); got != tt.want {
+				t.Errorf("Name(%d) = %s, want %s", tt.signum, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignalMaskRoundTrip(t *testing.T) {
+	masks := []uint64{
+		0,
+		0x0000000000004a02,
+		0x0000000180004002,
+		1 << 31, // 32 号信号
+		1 << 63, // 64 号信号
+		0xfffffffe7ffbfeff,
+		^uint64(0),
+	}
+
+	for _, arch := range SignalArchs {
+		table := mustTable(t, arch)
+		for _, mask := range masks {
+			names := table.Decode(mask)
+			got, err := table.Encode(names)
+			if err != nil {
+				t.Errorf("%s: Encode(Decode(%#x)) = %v", arch, mask, err)
+				continue
+			}
+			if got != mask {
+				t.Errorf("%s: Encode(Decode(%#x)) = %#x, names %v", arch, mask, got, names)
+			}
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package procinfo
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ListThreadStatus 解析 /proc/<pid>/task 下所有线程的状态，按 TID 排序
+// 返回的每个 ProcessStatus 的 PID 字段是线程的 TID；遍历过程中退出的线程会被跳过
+func ListThreadStatus(pid int, opts ParseOptions) ([]*ProcessStatus, error) {
+	taskDir := fmt.Sprintf("/proc/%d/task", pid)
+	entries, err := os.ReadDir(taskDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %d", ErrProcessNotFound, pid)
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", taskDir, err)
+	}
+
+	var tids []int
+	for _, e := range entries {
+		if tid, err := strconv.Atoi(e.Name()); err == nil {
+			tids = append(tids, tid)
+		}
+	}
+	sort.Ints(tids)
+
+	threads := make([]*ProcessStatus, 0, len(tids))
+	for _, tid := range tids {
+		status, err := parseStatusFile(fmt.Sprintf("%s/%d/status", taskDir, tid), tid, opts)
+		if err != nil {
+			if opts.Verbose {
+				fmt.Fprintf(os.Stderr, "[DEBUG] skip tid %d: %v\n", tid, err)
+			}
+			continue
+		}
+		threads = append(threads, status)
+	}
+	if len(threads) == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrProcessNotFound, pid)
+	}
+	return threads, nil
+}
+
+// ThreadGroup 一组 SigPnd、SigBlk 和 CapEff 都相同的线程
+// 这三个 mask 是线程私有的，SigIgn/SigCgt/ShdPnd 在线程组内共享，不参与分组
+type ThreadGroup struct {
+	Threads   []*ProcessStatus
+	Pending   uint64
+	Blocked   uint64
+	Effective uint64 // 未解析 Capabilities 时为 0
+}
+
+// GroupThreads 按线程私有的 mask 对线程分组，组的顺序与线程首次出现的顺序一致
+func GroupThreads(threads []*ProcessStatus) []*ThreadGroup {
+	type key struct{ pending, blocked, effective uint64 }
+
+	var groups []*ThreadGroup
+	index := make(map[key]*ThreadGroup)
+	for _, t := range threads {
+		var k key
+		if t.Signals != nil {
+			k.pending, k.blocked = t.Signals.Pending, t.Signals.Blocked
+		}
+		if t.Capabilities != nil {
+			k.effective = t.Capabilities.Effective
+		}
+		g, ok := index[k]
+		if !ok {
+			g = &ThreadGroup{Pending: k.pending, Blocked: k.blocked, Effective: k.effective}
+			index[k] = g
+			groups = append(groups, g)
+		}
+		g.Threads = append(g.Threads, t)
+	}
+	return groups
+}
+
+// SignalRecipient 描述进程级信号 (kill(pid, sig)) 会由哪个线程处理
+type SignalRecipient struct {
+	Signal     string `json:"signal"`
+	TID        int    `json:"tid"` // 0 表示没有线程会接收该信号
+	Name       string `json:"name,omitempty"`
+	Candidates int    `json:"candidates"` // 没有阻塞该信号的线程数
+	Reason     string `json:"reason"`
+}
+
+// ProcessSignalRecipient 按内核 complete_signal 的规则推断接收进程级信号的线程
+// 主线程没有阻塞该信号时优先选择主线程，否则选择一个没有阻塞该信号、也没有待处理信号的线程
+// 内核从上次投递的线程开始轮询，这里无法观察到该位置，因此返回 TID 最小的候选线程
+func ProcessSignalRecipient(pid int, threads []*ProcessStatus, signum int) *SignalRecipient {
+	r := &SignalRecipient{Signal: signalName(signum)}
+	if len(threads) == 0 {
+		r.Reason = "no thread was observed"
+		return r
+	}
+
+	leader := threads[0]
+	for _, t := range threads {
+		if t.PID == pid {
+			leader = t
+			break
+		}
+	}
+
+	var shared *SignalsInfo
+	for _, t := range threads {
+		if t.Signals != nil {
+			shared = t.Signals
+			break
+		}
+	}
+	if shared == nil {
+		r.Reason = "signal masks were not parsed"
+		return r
+	}
+
+	if signum == sigKill {
+		r.TID, r.Name = leader.PID, leader.Name
+		r.Candidates = len(threads)
+		r.Reason = "SIGKILL cannot be blocked or caught: the whole thread group is killed"
+		return r
+	}
+	if hasSignal(shared.Ignored, signum) {
+		r.Reason = r.Signal + " is ignored (SigIgn): the kernel discards it when it is sent"
+		return r
+	}
+
+	var unblocked, idle []*ProcessStatus
+	for _, t := range threads {
+		if t.Signals != nil && hasSignal(t.Signals.Blocked, signum) {
+			continue
+		}
+		unblocked = append(unblocked, t)
+		if t.Signals == nil || t.Signals.Pending == 0 {
+			idle = append(idle, t)
+		}
+	}
+	r.Candidates = len(unblocked)
+
+	var target *ProcessStatus
+	switch {
+	case len(unblocked) == 0:
+		r.Reason = fmt.Sprintf("all %d threads block %s: it stays in ShdPnd until a thread unblocks it", len(threads), r.Signal)
+		return r
+	case slices.Contains(idle, leader):
+		target = leader
+		r.Reason = "the main thread does not block it"
+	case len(idle) > 0:
+		target = idle[0]
+		if len(idle) == 1 {
+			r.Reason = "the only thread that neither blocks it nor has a signal pending"
+		} else {
+			r.Reason = fmt.Sprintf("the main thread cannot take it; the kernel picks one of %d eligible threads round-robin", len(idle))
+		}
+	default:
+		target = unblocked[0]
+		r.Reason = fmt.Sprintf("every thread that does not block it already has a signal pending; one of %d threads takes it", len(unblocked))
+	}
+	r.TID, r.Name = target.PID, target.Name
+
+	if !hasSignal(shared.Caught, signum) {
+		if len(leader.NSpid) > 0 && leader.NSpid[len(leader.NSpid)-1] == 1 {
+			r.Reason += "; no handler is installed and the process is PID 1 in its PID namespace, so the kernel drops it"
+		} else {
+			r.Reason += "; no handler is installed, so the default action applies to the whole process"
+		}
+	}
+	return r
+}
+
+// FormatThreadGroups 格式化线程分组和进程级信号的接收线程，接收线程以 * 标记
+// 每组默认只列出前几个线程，Verbose 时列出全部
+func FormatThreadGroups(pid int, name string, threads []*ProcessStatus, groups []*ThreadGroup, recipient *SignalRecipient, opts ParseOptions) string {
+	var sb strings.Builder
+	sb.WriteString("========== Threads ==========\n")
+	sb.WriteString(fmt.Sprintf("pid %d (%s): %d threads in %d groups\n", pid, name, len(threads), len(groups)))
+
+	for i, g := range groups {
+		sb.WriteString(fmt.Sprintf("\nGroup %d (%d threads): %s\n", i+1, len(g.Threads), formatThreadList(g.Threads, recipient, opts.Verbose)))
+		sb.WriteString(fmt.Sprintf("  SigPnd: %s\n", FormatSignalMask(g.Pending)))
+		sb.WriteString(fmt.Sprintf("  SigBlk: %s\n", FormatSignalMask(g.Blocked)))
+		if opts.ParseCapabilities {
+			sb.WriteString(fmt.Sprintf("  CapEff: %s\n", compactCapabilities(&CapabilitiesInfo{Effective: g.Effective})))
+		}
+	}
+
+	if recipient != nil {
+		sb.WriteString("\n")
+		if recipient.TID != 0 {
+			sb.WriteString(fmt.Sprintf("Process-directed %s -> * tid %d (%s): %s", recipient.Signal, recipient.TID, recipient.Name, recipient.Reason))
+		} else {
+			sb.WriteString(fmt.Sprintf("Process-directed %s -> no thread: %s", recipient.Signal, recipient.Reason))
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// formatThreadList 以 "TID NAME" 的形式列出线程，超过 maxCompactNames 个时以 +N 表示其余线程
+// 接收线程总是列出
+func formatThreadList(threads []*ProcessStatus, recipient *SignalRecipient, all bool) string {
+	var parts []string
+	hidden := 0
+	for i, t := range threads {
+		isRecipient := recipient != nil && recipient.TID != 0 && t.PID == recipient.TID
+		if !all && i >= maxCompactNames && !isRecipient {
+			hidden++
+			continue
+		}
+		mark := ""
+		if isRecipient {
+			mark = " *"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s%s", t.PID, t.Name, mark))
+	}
+	if hidden > 0 {
+		parts = append(parts, fmt.Sprintf("+%d", hidden))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// signalName 使用本机的信号表返回信号编号对应的名称
+func signalName(signum int) string {
+	return hostSignals.Name(signum)
+}
@@ -0,0 +1,186 @@
+package procinfo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sig 返回只包含 signum 的信号 mask
+func sig(signum int) uint64 {
+	return 1 << uint(signum-1)
+}
+
+// thread 构造一个线程的状态，SIGTERM 已安装处理函数
+func thread(tid int, name string, pending, blocked, effective uint64) *ProcessStatus {
+	return &ProcessStatus{
+		PID:          tid,
+		Name:         name,
+		Signals:      &SignalsInfo{Pending: pending, Blocked: blocked, Caught: sig(sigTerm)},
+		Capabilities: &CapabilitiesInfo{Effective: effective},
+	}
+}
+
+func TestGroupThreads(t *testing.T) {
+	tests := []struct {
+		name    string
+		threads []*ProcessStatus
+		want    [][]int // 每组线程的 TID
+	}{
+		{name: "empty"},
+		{
+			name: "identical threads",
+			threads: []*ProcessStatus{
+				thread(100, "app", 0, 0, 0),
+				thread(101, "app", 0, 0, 0),
+			},
+			want: [][]int{{100, 101}},
+		},
+		{
+			name: "blocked, pending and capabilities split groups",
+			threads: []*ProcessStatus{
+				thread(100, "app", 0, 0, 0),
+				thread(101, "worker", 0, sig(sigTerm), 0),
+				thread(102, "worker", 0, sig(sigTerm), 0),
+				thread(103, "io", sig(10), 0, 0),
+				thread(104, "net", 0, 0, 1<<12),
+				thread(105, "app", 0, 0, 0),
+			},
+			want: [][]int{{100, 105}, {101, 102}, {103}, {104}},
+		},
+		{
+			name: "masks not parsed",
+			threads: []*ProcessStatus{
+				{PID: 100, Name: "app"},
+				{PID: 101, Name: "app"},
+			},
+			want: [][]int{{100, 101}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]int
+			for _, g := range GroupThreads(tt.threads) {
+				var tids []int
+				for _, th := range g.Threads {
+					tids = append(tids, th.PID)
+				}
+				got = append(got, tids)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GroupThreads = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessSignalRecipient(t *testing.T) {
+	tests := []struct {
+		name       string
+		pid        int
+		threads    []*ProcessStatus
+		signum     int
+		wantTID    int
+		wantCands  int
+		wantReason string
+	}{
+		{
+			name: "leader idle",
+			pid:  100,
+			threads: []*ProcessStatus{
+				thread(100, "app", 0, 0, 0),
+				thread(101, "worker", 0, 0, 0),
+			},
+			signum:     sigTerm,
+			wantTID:    100,
+			wantCands:  2,
+			wantReason: "the main thread does not block it",
+		},
+		{
+			name: "leader blocking",
+			pid:  100,
+			threads: []*ProcessStatus{
+				thread(100, "app", 0, sig(sigTerm), 0),
+				thread(101, "worker", 0, 0, 0),
+			},
+			signum:     sigTerm,
+			wantTID:    101,
+			wantCands:  1,
+			wantReason: "the only thread that neither blocks it nor has a signal pending",
+		},
+		{
+			name: "leader with a signal pending",
+			pid:  100,
+			threads: []*ProcessStatus{
+				thread(100, "app", sig(10), 0, 0),
+				thread(101, "worker", 0, 0, 0),
+				thread(102, "worker", 0, 0, 0),
+			},
+			signum:     sigTerm,
+			wantTID:    101,
+			wantCands:  3,
+			wantReason: "the main thread cannot take it",
+		},
+		{
+			name: "all threads blocking",
+			pid:  100,
+			threads: []*ProcessStatus{
+				thread(100, "app", 0, sig(sigTerm), 0),
+				thread(101, "worker", 0, sig(sigTerm), 0),
+			},
+			signum:     sigTerm,
+			wantTID:    0,
+			wantCands:  0,
+			wantReason: "all 2 threads block SIGTERM",
+		},
+		{
+			name: "leader TID above a worker after wrap-around",
+			pid:  300,
+			threads: []*ProcessStatus{
+				thread(250, "worker", 0, 0, 0),
+				thread(300, "app", 0, 0, 0),
+			},
+			signum:     sigTerm,
+			wantTID:    300,
+			wantCands:  2,
+			wantReason: "the main thread does not block it",
+		},
+		{
+			name: "SIGKILL ignores masks",
+			pid:  100,
+			threads: []*ProcessStatus{
+				thread(100, "app", 0, 0, 0),
+				thread(101, "worker", 0, 0, 0),
+			},
+			signum:     sigKill,
+			wantTID:    100,
+			wantCands:  2,
+			wantReason: "SIGKILL cannot be blocked",
+		},
+		{
+			name: "no handler",
+			pid:  100,
+			threads: []*ProcessStatus{
+				thread(100, "app", 0, 0, 0),
+			},
+			signum:     2,
+			wantTID:    100,
+			wantCands:  1,
+			wantReason: "no handler is installed, so the default action applies",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := ProcessSignalRecipient(tt.pid, tt.threads, tt.signum)
+			if r.TID != tt.wantTID || r.Candidates != tt.wantCands {
+				t.Errorf("recipient = tid %d, %d candidates; want tid %d, %d candidates (%s)",
+					r.TID, r.Candidates, tt.wantTID, tt.wantCands, r.Reason)
+			}
+			if !strings.Contains(r.Reason, tt.wantReason) {
+				t.Errorf("reason = %q, want it to contain %q", r.Reason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package procinfo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ProcessNode 进程树中的一个节点
+type ProcessNode struct {
+	Status   *ProcessStatus
+	Children []*ProcessNode
+
+	// 相对父进程的变化，根节点没有父进程，两者都为零值
+	Gained     uint64 // CapEff 中父进程没有的 capability (setuid root、文件 capability 等)
+	BecameRoot bool   // 父进程的有效 UID 不是 0，而该进程是 0
+}
+
+// Escalated 判断该进程相对父进程是否获得了额外权限
+func (n *ProcessNode) Escalated() bool {
+	return n.Gained != 0 || n.BecameRoot
+}
+
+// BuildProcessTree 按 PPid 将进程组织为树，返回按 PID 排序的根节点
+// PPid 不在列表中的进程 (如 PID 1、容器内 exec 启动的进程) 作为根节点
+// 比较 CapEff 需要解析 Capabilities，未解析时不标记 capability 的增长
+func BuildProcessTree(statuses []*ProcessStatus) []*ProcessNode {
+	nodes := make(map[int]*ProcessNode, len(statuses))
+	for _, status := range statuses {
+		nodes[status.PID] = &ProcessNode{Status: status}
+	}
+
+	var roots []*ProcessNode
+	for _, status := range statuses {
+		node := nodes[status.PID]
+		parent, ok := nodes[status.PPid]
+		if !ok || status.PPid == status.PID {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+
+		if status.Capabilities != nil && parent.Status.Capabilities != nil {
+			node.Gained = status.Capabilities.Effective &^ parent.Status.Capabilities.Effective
+		}
+		node.BecameRoot = status.UID.Effective == 0 && parent.Status.UID.Effective != 0
+	}
+
+	sortNodes(roots)
+	for _, node := range nodes {
+		sortNodes(node.Children)
+	}
+	return roots
+}
+
+// FindProcessNode 在树中查找指定 PID 的节点，找不到时返回 nil
+func FindProcessNode(roots []*ProcessNode, pid int) *ProcessNode {
+	for _, node := range roots {
+		if node.Status.PID == pid {
+			return node
+		}
+		if found := FindProcessNode(node.Children, pid); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func sortNodes(nodes []*ProcessNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Status.PID < nodes[j].Status.PID
+	})
+}
+
+// FormatProcessTree 以树形格式化进程，每个节点一行，标注有效 UID、CapEff 和 NoNewPrivs
+// 相对父进程获得额外权限的节点以 ! 标记，并列出新增的 capability
+func FormatProcessTree(roots []*ProcessNode) string {
+	var sb strings.Builder
+	for _, root := range roots {
+		writeProcessNode(&sb, root, "", "")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// writeProcessNode 写入一个节点及其子树
+// prefix 是该节点行的前缀，childPrefix 是其子节点行的前缀
+func writeProcessNode(sb *strings.Builder, node *ProcessNode, prefix, childPrefix string) {
+	sb.WriteString(prefix)
+	sb.WriteString(formatNodeLabel(node))
+	sb.WriteString("\n")
+
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			writeProcessNode(sb, child, childPrefix+"└─ ", childPrefix+"   ")
+		} else {
+			writeProcessNode(sb, child, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+}
+
+// formatNodeLabel 返回节点的单行描述，如 "12 nginx  uid=101  cap=-  nnp=1"
+func formatNodeLabel(node *ProcessNode) string {
+	status := node.Status
+	parts := []string{
+		fmt.Sprintf("%d %s", status.PID, status.Name),
+		fmt.Sprintf("uid=%d", status.UID.Effective),
+		"cap=" + compactCapabilities(status.Capabilities),
+	}
+	if status.Security != nil {
+		nnp := 0
+		if status.Security.NoNewPrivs {
+			nnp = 1
+		}
+		parts = append(parts, fmt.Sprintf("nnp=%d", nnp))
+	}
+
+	if node.Escalated() {
+		var gained []string
+		if node.Gained != 0 {
+			gained = append(gained, "gained "+compactNames(DecodeCapabilityMask(node.Gained), "CAP_"))
+		}
+		if node.BecameRoot {
+			gained = append(gained, "became root")
+		}
+		parts = append(parts, "! "+strings.Join(gained, ", "))
+	}
+	return strings.Join(parts, "  ")
+}
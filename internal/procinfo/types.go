@@ -2,11 +2,32 @@ package procinfo
 
 // ProcessStatus 表示进程状态信息
 type ProcessStatus struct {
-	PID          int
-	Name         string
-	State        string
+	PID   int
+	Name  string
+	State string
+
+	// 进程标识 (总是解析)
+	PPid    int
+	Threads int
+	NSpid   []int // 各级 PID 命名空间中的 PID，最后一个是最内层（容器内）的 PID
+	NStgid  []int // 各级 PID 命名空间中的线程组 ID
+	UID     IDs   // Uid 行
+	GID     IDs   // Gid 行
+	Groups  []int // 附加组
+
 	Capabilities *CapabilitiesInfo
 	Signals      *SignalsInfo
+	Memory       *MemoryInfo
+	Security     *SecurityInfo
+	Scheduling   *SchedulingInfo
+}
+
+// IDs 表示 Uid/Gid 行中的四个 ID
+type IDs struct {
+	Real      int `json:"real"`
+	Effective int `json:"effective"`
+	Saved     int `json:"saved"`
+	FS        int `json:"fs"`
 }
 
 // CapabilitiesInfo 表示进程的 Linux Capabilities 信息
@@ -28,9 +49,34 @@ type SignalsInfo struct {
 	Caught        uint64 // SigCgt (被捕获的信号)
 }
 
+// MemoryInfo 表示进程的内存使用 (单位: kB)
+type MemoryInfo struct {
+	VmRSS uint64 // 当前常驻内存
+	VmHWM uint64 // 常驻内存峰值
+}
+
+// SecurityInfo 表示进程的 seccomp 和 no_new_privs 状态
+type SecurityInfo struct {
+	Seccomp        int  // 0: disabled, 1: strict, 2: filter
+	SeccompFilters int  // Seccomp_filters，已加载的过滤器数量
+	NoNewPrivs     bool // NoNewPrivs
+}
+
+// SchedulingInfo 表示进程的 CPU 亲和性和上下文切换次数
+type SchedulingInfo struct {
+	CpusAllowedList          string // Cpus_allowed_list
+	VoluntaryCtxtSwitches    uint64 // voluntary_ctxt_switches
+	NonvoluntaryCtxtSwitches uint64 // nonvoluntary_ctxt_switches
+}
+
 // ParseOptions 解析选项
+// ParseIdentity 只影响输出，进程标识字段总是会被解析
 type ParseOptions struct {
+	ParseIdentity     bool
 	ParseCapabilities bool
 	ParseSignals      bool
+	ParseMemory       bool
+	ParseSecurity     bool
+	ParseScheduling   bool
 	Verbose           bool
 }
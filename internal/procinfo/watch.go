@@ -0,0 +1,65 @@
+package procinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	sigKill = 9
+	sigTerm = 15
+)
+
+// ExplainExit 根据进程退出前最后一次观察到的状态解释 SIGTERM 的处理情况
+// 用于排查优雅退出失败：SIGTERM 被阻塞、被忽略，或 PID 1 没有安装处理函数
+func ExplainExit(last *ProcessStatus) []string {
+	if last == nil || last.Signals == nil {
+		return nil
+	}
+	sigs := last.Signals
+	pending := sigs.Pending | sigs.SharedPending
+
+	var lines []string
+	if hasSignal(pending, sigKill) {
+		lines = append(lines, "SIGKILL was pending: the process was killed (e.g. terminationGracePeriodSeconds expired)")
+	}
+
+	switch {
+	case hasSignal(pending, sigTerm) && hasSignal(sigs.Blocked, sigTerm):
+		lines = append(lines, "SIGTERM was pending but blocked (SigBlk): the process never unblocked it, so graceful shutdown did not start")
+	case hasSignal(sigs.Ignored, sigTerm):
+		lines = append(lines, "SIGTERM is ignored (SigIgn): the process cannot react to graceful termination and is only stopped by SIGKILL")
+	case hasSignal(sigs.Caught, sigTerm):
+		if hasSignal(pending, sigTerm) {
+			lines = append(lines, "SIGTERM was pending and has a handler (SigCgt): the handler had not run yet at the last sample")
+		} else {
+			lines = append(lines, "SIGTERM has a handler (SigCgt): the exit is most likely the result of the application's own shutdown")
+		}
+	case len(last.NSpid) > 0 && last.NSpid[len(last.NSpid)-1] == 1:
+		lines = append(lines, "SIGTERM has no handler and the process is PID 1 in its PID namespace: the kernel drops SIGTERM for it, so only SIGKILL can stop it")
+	default:
+		lines = append(lines, "SIGTERM has the default disposition: the process terminates as soon as it is delivered")
+	}
+
+	if strings.HasPrefix(last.State, "Z") {
+		lines = append(lines, "the process was a zombie at the last sample: it had exited and was waiting for its parent to reap it")
+	}
+	return lines
+}
+
+// FormatSignalTransition 格式化一个集合的变化，如 "SigPnd +SIGTERM -SIGHUP"
+func FormatSignalTransition(d SetDiff) string {
+	var parts []string
+	for _, n := range d.Added {
+		parts = append(parts, "+"+n)
+	}
+	for _, n := range d.Removed {
+		parts = append(parts, "-"+n)
+	}
+	return fmt.Sprintf("%s %s", d.Set, strings.Join(parts, " "))
+}
+
+// hasSignal 判断 mask 中是否包含指定信号
+func hasSignal(mask uint64, signum int) bool {
+	return mask&(1<<uint(signum-1)) != 0
+}